@@ -0,0 +1,558 @@
+// Package btree implements an in-memory B-tree, which keeps many data per node so that it
+// needs far fewer nodes and pointers than a binary search tree of the same data.
+package btree
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/stack"
+	"github.com/NzKSO/container/tree"
+)
+
+var _ tree.Tree = (*BTree)(nil)
+
+// DefaultDegree is the minimum degree used by NewBTree when it's given a degree less than 2.
+const DefaultDegree = 32
+
+type node struct {
+	items    []container.Interface
+	children []*node // nil for leaves, otherwise one more than items
+}
+
+func (n *node) leaf() bool {
+	return n.children == nil
+}
+
+// search returns the index of the first item of n found by key or ordered after it, and
+// whether it is found by key.
+func (n *node) search(key interface{}) (int, bool) {
+	i := sort.Search(len(n.items), func(i int) bool {
+		return n.items[i].Find(key) || !n.items[i].Less(key)
+	})
+	return i, i < len(n.items) && n.items[i].Find(key)
+}
+
+// BTree represents a B-tree of minimum degree t, where every node but the root holds between
+// t-1 and 2t-1 data, and all leaves are at the same depth. Data are ordered by Less and found
+// by Find the same way as in tree.BSTree. Every structural modification of the tree is counted,
+// so that traversals can detect it.
+type BTree struct {
+	rw     sync.RWMutex
+	root   *node
+	degree int
+	size   int
+	mods   uint64
+}
+
+// NewBTree returns an empty B-tree of minimum degree degree, or DefaultDegree if degree is less than 2.
+func NewBTree(degree int) *BTree {
+	if degree < 2 {
+		degree = DefaultDegree
+	}
+	return &BTree{degree: degree}
+}
+
+// Degree returns the minimum degree of the tree.
+func (bt *BTree) Degree() int {
+	return bt.degree
+}
+
+func (bt *BTree) maxItems() int {
+	return 2*bt.degree - 1
+}
+
+// step records which item or child of a node was taken on the way down.
+type step struct {
+	n *node
+	i int
+}
+
+// locate returns the path from the root to the item found by key, where the last step holds
+// the index of the item and the others the index of the child taken. It returns nil if not found.
+func (bt *BTree) locate(key interface{}) []step {
+	path := make([]step, 0, 8)
+	for n := bt.root; n != nil; {
+		i, found := n.search(key)
+		path = append(path, step{n, i})
+		if found {
+			return path
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return nil
+}
+
+// contains reports whether the tree holds the data found by key.
+func (bt *BTree) contains(key interface{}) bool {
+	for n := bt.root; n != nil; {
+		i, found := n.search(key)
+		if found {
+			return true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return false
+}
+
+// splitChild splits the full child i of n in two around its median, which moves up to n.
+func (bt *BTree) splitChild(n *node, i int) {
+	t := bt.degree
+	child := n.children[i]
+	right := &node{items: append([]container.Interface(nil), child.items[t:]...)}
+	if !child.leaf() {
+		right.children = append([]*node(nil), child.children[t:]...)
+		child.children = child.children[:t:t]
+	}
+	median := child.items[t-1]
+	child.items = child.items[: t-1 : t-1]
+
+	n.items = append(n.items, nil)
+	copy(n.items[i+1:], n.items[i:])
+	n.items[i] = median
+	n.children = append(n.children, nil)
+	copy(n.children[i+2:], n.children[i+1:])
+	n.children[i+1] = right
+}
+
+// insert inserts data, which must not be in the tree yet, splitting full nodes on the way down.
+func (bt *BTree) insert(data container.Interface) {
+	if bt.root == nil {
+		bt.root = &node{}
+	}
+	if len(bt.root.items) == bt.maxItems() {
+		bt.root = &node{children: []*node{bt.root}}
+		bt.splitChild(bt.root, 0)
+	}
+
+	n := bt.root
+	for {
+		i, _ := n.search(data)
+		if n.leaf() {
+			n.items = append(n.items, nil)
+			copy(n.items[i+1:], n.items[i:])
+			n.items[i] = data
+			break
+		}
+
+		if len(n.children[i].items) == bt.maxItems() {
+			bt.splitChild(n, i)
+			if n.items[i].Less(data) {
+				i++
+			}
+		}
+		n = n.children[i]
+	}
+	bt.size++
+	bt.mods++
+}
+
+// Insert inserts data to the tree. If data already exists, returns ErrDataExists.
+func (bt *BTree) Insert(data container.Interface) error {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if bt.contains(data) {
+		return container.ErrDataExists
+	}
+	bt.insert(data)
+	return nil
+}
+
+// Search searches the tree for the data found by key. If the tree is empty, returns ErrEmptyTree.
+// If not found, returns ErrNotExist.
+func (bt *BTree) Search(key interface{}) (interface{}, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.size == 0 {
+		return nil, container.ErrEmptyTree
+	}
+
+	path := bt.locate(key)
+	if path == nil {
+		return nil, container.ErrNotExist
+	}
+	last := path[len(path)-1]
+	return last.n.items[last.i], nil
+}
+
+// Delete deletes the data found by key. If the tree is empty, returns ErrEmptyTree, if the data
+// doesn't exist, returns ErrNotExist.
+func (bt *BTree) Delete(key interface{}) error {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if bt.size == 0 {
+		return container.ErrEmptyTree
+	}
+
+	path := bt.locate(key)
+	if path == nil {
+		return container.ErrNotExist
+	}
+	bt.removeAt(path)
+	return nil
+}
+
+// removeAt removes the item the path returned by locate leads to, and refills the nodes left with
+// too few items on the way back up.
+func (bt *BTree) removeAt(path []step) {
+	last := path[len(path)-1]
+	if !last.n.leaf() {
+		// The item is replaced by its predecessor, the largest item of its left subtree.
+		n := last.n.children[last.i]
+		for !n.leaf() {
+			path = append(path, step{n, len(n.children) - 1})
+			n = n.children[len(n.children)-1]
+		}
+		path = append(path, step{n, len(n.items) - 1})
+		last.n.items[last.i] = n.items[len(n.items)-1]
+		last = path[len(path)-1]
+	}
+	last.n.items = append(last.n.items[:last.i], last.n.items[last.i+1:]...)
+
+	for k := len(path) - 1; k > 0 && len(path[k].n.items) < bt.degree-1; k-- {
+		bt.refill(path[k-1].n, path[k-1].i)
+	}
+	if len(bt.root.items) == 0 {
+		if bt.root.leaf() {
+			bt.root = nil
+		} else {
+			bt.root = bt.root.children[0]
+		}
+	}
+	bt.size--
+	bt.mods++
+}
+
+// refill brings child i of n back to t-1 items, by borrowing an item from a sibling through n,
+// or if neither sibling has one to spare, by merging it with a sibling.
+func (bt *BTree) refill(n *node, i int) {
+	child := n.children[i]
+	if i > 0 && len(n.children[i-1].items) >= bt.degree {
+		left := n.children[i-1]
+		child.items = append([]container.Interface{n.items[i-1]}, child.items...)
+		n.items[i-1] = left.items[len(left.items)-1]
+		left.items = left.items[:len(left.items)-1]
+		if !left.leaf() {
+			child.children = append([]*node{left.children[len(left.children)-1]}, child.children...)
+			left.children = left.children[:len(left.children)-1]
+		}
+		return
+	}
+	if i < len(n.children)-1 && len(n.children[i+1].items) >= bt.degree {
+		right := n.children[i+1]
+		child.items = append(child.items, n.items[i])
+		n.items[i] = right.items[0]
+		right.items = append(right.items[:0], right.items[1:]...)
+		if !right.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = append(right.children[:0], right.children[1:]...)
+		}
+		return
+	}
+
+	if i > 0 {
+		i--
+	}
+	// Merge child i+1 into child i together with the item separating them.
+	left, right := n.children[i], n.children[i+1]
+	left.items = append(append(left.items, n.items[i]), right.items...)
+	if !left.leaf() {
+		left.children = append(left.children, right.children...)
+	}
+	n.items = append(n.items[:i], n.items[i+1:]...)
+	n.children = append(n.children[:i+1], n.children[i+2:]...)
+}
+
+// Update updates the data found by key with val using Set. If the tree is empty, returns ErrEmptyTree.
+// If not found, returns ErrNotExist. Set must not change the key of the data, if it does, Update moves
+// the data to where its new key belongs and returns ErrKeyChanged, unless the new key is already used
+// by other data, in which case the updated data is taken out of the tree, as it can't stay where its
+// old key belongs, and ErrDataExists is returned.
+func (bt *BTree) Update(key interface{}, val interface{}) error {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if bt.size == 0 {
+		return container.ErrEmptyTree
+	}
+
+	path := bt.locate(key)
+	if path == nil {
+		return container.ErrNotExist
+	}
+	last := path[len(path)-1]
+	v := last.n.items[last.i]
+	v.Set(val)
+	if v.Find(key) {
+		return nil
+	}
+
+	bt.removeAt(path)
+	if bt.contains(v) {
+		return container.ErrDataExists
+	}
+	bt.insert(v)
+	return container.ErrKeyChanged
+}
+
+// Min returns the smallest data in the tree. If the tree is empty, returns ErrEmptyTree.
+func (bt *BTree) Min() (interface{}, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil {
+		return nil, container.ErrEmptyTree
+	}
+	n := bt.root
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return n.items[0], nil
+}
+
+// Max returns the largest data in the tree. If the tree is empty, returns ErrEmptyTree.
+func (bt *BTree) Max() (interface{}, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil {
+		return nil, container.ErrEmptyTree
+	}
+	n := bt.root
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	return n.items[len(n.items)-1], nil
+}
+
+// ascend calls visit on every item in order, starting from the one found by key or the first one
+// ordered after it, or from the smallest if from is false, until visit returns false.
+func (bt *BTree) ascend(from bool, key interface{}, visit func(data container.Interface) bool) {
+	// Every frame holds a node and the index of its next item to visit, the items before it and
+	// the subtrees left of them having been visited. The frames are kept in a slice rather than
+	// a stack.LStack, which would allocate for every push.
+	frames := make([]step, 0, 8)
+	for n := bt.root; n != nil; {
+		i := 0
+		found := false
+		if from {
+			i, found = n.search(key)
+		}
+		frames = append(frames, step{n, i})
+		if found || n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+
+	for len(frames) > 0 {
+		f := &frames[len(frames)-1]
+		if f.i == len(f.n.items) {
+			frames = frames[:len(frames)-1]
+			continue
+		}
+		if !visit(f.n.items[f.i]) {
+			return
+		}
+		f.i++
+		if !f.n.leaf() {
+			for n := f.n.children[f.i]; n != nil; {
+				frames = append(frames, step{n, 0})
+				if n.leaf() {
+					break
+				}
+				n = n.children[0]
+			}
+		}
+	}
+}
+
+// Ascend calls visit on every data in order until visit returns false. visit must not modify the tree.
+func (bt *BTree) Ascend(visit func(data interface{}) bool) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	bt.ascend(false, nil, func(data container.Interface) bool {
+		return visit(data)
+	})
+}
+
+// Range calls visit in order on every data whose key lies between lo and hi inclusive, until visit
+// returns false. visit must not modify the tree.
+func (bt *BTree) Range(lo, hi interface{}, visit func(data interface{}) bool) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	bt.ascend(true, lo, func(data container.Interface) bool {
+		if !data.Find(hi) && !data.Less(hi) {
+			return false
+		}
+		return visit(data)
+	})
+}
+
+// Traversal traverses the tree in the order given by travType, which is one of the traversal types
+// of tree.BSTree. The data of a B-tree can only be traversed in order, so tree.InorderTrav and
+// tree.MorrisInorderTrav yield the same sequence, and ErrNotSupported is sent as the only value for
+// other types. If the tree is structurally modified before the traversal ends, it stops and
+// ErrConcurrentModification is sent as the last value.
+func (bt *BTree) Traversal(travType tree.TraversalType) <-chan interface{} {
+	ch := make(chan interface{})
+	if travType != tree.InorderTrav && travType != tree.MorrisInorderTrav {
+		ch = make(chan interface{}, 1)
+		ch <- container.ErrNotSupported
+		close(ch)
+		return ch
+	}
+
+	bt.rw.RLock()
+	mods := bt.mods
+	bt.rw.RUnlock()
+
+	go func() {
+		defer close(ch)
+
+		// The tree is only locked while walking, not while blocked on sending.
+		bt.rw.RLock()
+		defer bt.rw.RUnlock()
+		if bt.mods != mods {
+			ch <- container.ErrConcurrentModification
+			return
+		}
+
+		bt.ascend(false, nil, func(data container.Interface) bool {
+			bt.rw.RUnlock()
+			ch <- data
+			bt.rw.RLock()
+
+			if bt.mods != mods {
+				bt.rw.RUnlock()
+				ch <- container.ErrConcurrentModification
+				bt.rw.RLock()
+				return false
+			}
+			return true
+		})
+	}()
+
+	return ch
+}
+
+// Size returns the number of data in the tree.
+func (bt *BTree) Size() int {
+	return bt.size
+}
+
+// Empty returns true if the tree is empty, otherwise false.
+func (bt *BTree) Empty() bool {
+	return bt.size == 0
+}
+
+// Reset drops all of data in the tree and back to its initial state, keeping its degree.
+func (bt *BTree) Reset() {
+	bt.rw.Lock()
+	bt.root = nil
+	bt.size = 0
+	bt.mods++
+	bt.rw.Unlock()
+}
+
+// Height returns the number of edges on the path from the root to any leaf, or -1 if the tree is empty.
+func (bt *BTree) Height() int {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	h := -1
+	for n := bt.root; n != nil; h++ {
+		if n.leaf() {
+			n = nil
+		} else {
+			n = n.children[0]
+		}
+	}
+	return h
+}
+
+// bound records a node together with its depth and the items bounding its items from below and above.
+type bound struct {
+	n            *node
+	depth        int
+	lower, upper container.Interface
+}
+
+// Validate checks the invariants of the tree: items of every node are ordered and lie between the
+// items of its parent that bound it, every node but the root holds between t-1 and 2t-1 items and
+// every inner node one child more than items, all leaves are at the same depth, and size equals
+// the number of items. It returns nil if the tree is consistent, otherwise an error wrapping ErrCorrupted.
+func (bt *BTree) Validate() error {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil {
+		if bt.size != 0 {
+			return fmt.Errorf("%w: empty tree has size %d", container.ErrCorrupted, bt.size)
+		}
+		return nil
+	}
+
+	count, leafDepth := 0, -1
+	ls := stack.NewLStack()
+	ls.Push(bound{n: bt.root})
+	for !ls.Empty() {
+		b := ls.Pop().(bound)
+		n := b.n
+		if len(n.items) > bt.maxItems() || len(n.items) == 0 || n != bt.root && len(n.items) < bt.degree-1 {
+			return fmt.Errorf("%w: node %v has %d items", container.ErrCorrupted, n.items, len(n.items))
+		}
+		count += len(n.items)
+
+		prev := b.lower
+		for _, item := range n.items {
+			if prev != nil && (prev.Find(item) || !prev.Less(item)) {
+				return fmt.Errorf("%w: %v is not ordered after %v", container.ErrCorrupted, item, prev)
+			}
+			prev = item
+		}
+		if b.upper != nil && (prev.Find(b.upper) || !prev.Less(b.upper)) {
+			return fmt.Errorf("%w: %v is not ordered before %v", container.ErrCorrupted, prev, b.upper)
+		}
+
+		if n.leaf() {
+			if leafDepth == -1 {
+				leafDepth = b.depth
+			} else if leafDepth != b.depth {
+				return fmt.Errorf("%w: leaves at depth %d and %d", container.ErrCorrupted, leafDepth, b.depth)
+			}
+			continue
+		}
+		if len(n.children) != len(n.items)+1 {
+			return fmt.Errorf("%w: node %v has %d children", container.ErrCorrupted, n.items, len(n.children))
+		}
+		for i, child := range n.children {
+			lower, upper := b.lower, b.upper
+			if i > 0 {
+				lower = n.items[i-1]
+			}
+			if i < len(n.items) {
+				upper = n.items[i]
+			}
+			ls.Push(bound{child, b.depth + 1, lower, upper})
+		}
+	}
+
+	if count != bt.size {
+		return fmt.Errorf("%w: size is %d but tree has %d items", container.ErrCorrupted, bt.size, count)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package btree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/btree"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+const benchSize = 1 << 20
+
+// inserter is implemented by both BTree and BSTree.
+type inserter interface {
+	Insert(data container.Interface) error
+	Search(key interface{}) (interface{}, error)
+}
+
+func benchmarkInsert(b *testing.B, create func() inserter) {
+	perm := r.Perm(benchSize)
+	data := make([]testdata.Corp, benchSize)
+	for i, id := range perm {
+		data[i].ID = id
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		t := create()
+		for i := range data {
+			t.Insert(&data[i])
+		}
+	}
+}
+
+func benchmarkSearch(b *testing.B, t inserter) {
+	data := make([]testdata.Corp, benchSize)
+	for i, id := range r.Perm(benchSize) {
+		data[i].ID = id
+		t.Insert(&data[i])
+	}
+	keys := r.Perm(benchSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		t.Search(keys[n%benchSize])
+	}
+}
+
+func BenchmarkBTreeInsert(b *testing.B) {
+	benchmarkInsert(b, func() inserter { return btree.NewBTree(btree.DefaultDegree) })
+}
+
+func BenchmarkBSTreeInsert(b *testing.B) {
+	benchmarkInsert(b, func() inserter { return tree.NewBSTree() })
+}
+
+func BenchmarkBTreeSearch(b *testing.B) {
+	benchmarkSearch(b, btree.NewBTree(btree.DefaultDegree))
+}
+
+func BenchmarkBSTreeSearch(b *testing.B) {
+	benchmarkSearch(b, tree.NewBSTree())
+}
+
+func BenchmarkBTreeFromSorted(b *testing.B) {
+	seq := make([]container.Interface, benchSize)
+	for i := range seq {
+		seq[i] = &testdata.Corp{ID: i}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		btree.FromSorted(seq, btree.DefaultDegree)
+	}
+}
+
+func BenchmarkBTreeAscend(b *testing.B) {
+	seq := make([]container.Interface, benchSize)
+	for i := range seq {
+		seq[i] = &testdata.Corp{ID: i}
+	}
+	bt, _ := btree.FromSorted(seq, btree.DefaultDegree)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		bt.Ascend(func(interface{}) bool { return true })
+	}
+}
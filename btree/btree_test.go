@@ -0,0 +1,324 @@
+package btree_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/btree"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func createBTree(degree int, ids []int) *btree.BTree {
+	bt := btree.NewBTree(degree)
+	for _, id := range ids {
+		bt.Insert(&testdata.Corp{ID: id})
+	}
+	return bt
+}
+
+func idsOf(bt *btree.BTree) []int {
+	var ids []int
+	bt.Ascend(func(data interface{}) bool {
+		ids = append(ids, data.(*testdata.Corp).ID)
+		return true
+	})
+	return ids
+}
+
+func compareIntSlice(s1, s2 []int) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if s1[i] != s2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBTreeInsertSearch(t *testing.T) {
+	bt := btree.NewBTree(2)
+	if _, err := bt.Search(0); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+
+	for _, iv := range r.Perm(len(testdata.TestCases)) {
+		if err := bt.Insert(&testdata.TestCases[iv]); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+		if err := bt.Validate(); err != nil {
+			t.Fatalf("%v != nil", err)
+		}
+	}
+	if bt.Size() != len(testdata.TestCases) || bt.Empty() {
+		t.Errorf("%v != %v", bt.Size(), len(testdata.TestCases))
+	}
+
+	for iv := range testdata.TestCases {
+		if itf, err := bt.Search(testdata.TestCases[iv].ID); err != nil || itf != &testdata.TestCases[iv] {
+			t.Errorf("(%v != nil) or (%v != %v)", err, itf, &testdata.TestCases[iv])
+		}
+		if err := bt.Insert(&testdata.Corp{ID: testdata.TestCases[iv].ID}); err != container.ErrDataExists {
+			t.Errorf("%v != %v", err, container.ErrDataExists)
+		}
+	}
+	if _, err := bt.Search(-1); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestBTreeDelete(t *testing.T) {
+	for _, degree := range []int{2, 3, 5} {
+		bt := createBTree(degree, r.Perm(500))
+		if err := bt.Delete(500); err != container.ErrNotExist {
+			t.Errorf("%v != %v", err, container.ErrNotExist)
+		}
+
+		for i, id := range r.Perm(500) {
+			if err := bt.Delete(id); err != nil {
+				t.Errorf("%v != nil", err)
+			}
+			if bt.Size() != 500-i-1 {
+				t.Errorf("%v != %v", bt.Size(), 500-i-1)
+			}
+			if err := bt.Validate(); err != nil {
+				t.Fatalf("degree %v: %v != nil", degree, err)
+			}
+		}
+		if !bt.Empty() || bt.Height() != -1 {
+			t.Errorf("tree is not empty")
+		}
+		if err := bt.Delete(0); err != container.ErrEmptyTree {
+			t.Errorf("%v != %v", err, container.ErrEmptyTree)
+		}
+	}
+}
+
+func TestBTreeRandom(t *testing.T) {
+	bt := btree.NewBTree(3)
+	model := make(map[int]bool)
+	for i := 0; i < 20000; i++ {
+		id := r.Intn(1000)
+		if r.Intn(2) == 0 {
+			err := bt.Delete(id)
+			if model[id] != (err == nil) {
+				t.Fatalf("Delete(%v): %v", id, err)
+			}
+			delete(model, id)
+		} else {
+			err := bt.Insert(&testdata.Corp{ID: id})
+			if model[id] != (err == container.ErrDataExists) {
+				t.Fatalf("Insert(%v): %v", id, err)
+			}
+			model[id] = true
+		}
+	}
+
+	if err := bt.Validate(); err != nil || bt.Size() != len(model) {
+		t.Errorf("(%v != nil) or (%v != %v)", err, bt.Size(), len(model))
+	}
+	prev := -1
+	for _, id := range idsOf(bt) {
+		if id <= prev || !model[id] {
+			t.Fatalf("%v after %v", id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestBTreeUpdate(t *testing.T) {
+	bt := createBTree(2, r.Perm(20))
+	if err := bt.Update(7, "Intel"); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if itf, _ := bt.Search(7); itf.(*testdata.Corp).Name != "Intel" {
+		t.Errorf("%v != Intel", itf)
+	}
+	if err := bt.Update(20, "Intel"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+
+	// Set of IDSetter changes the key.
+	bt = btree.NewBTree(2)
+	for _, id := range r.Perm(20) {
+		bt.Insert(&testdata.IDSetter{testdata.Corp{ID: id}})
+	}
+	if err := bt.Update(7, 25); err != container.ErrKeyChanged {
+		t.Errorf("%v != %v", err, container.ErrKeyChanged)
+	}
+	if _, err := bt.Search(&testdata.IDSetter{testdata.Corp{ID: 25}}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	// The new key is used by other data, which stays, and the updated data is taken out.
+	for n, id := range []int{0, 8, 19} {
+		other, _ := bt.Search(25)
+		if err := bt.Update(id, 25); err != container.ErrDataExists || bt.Size() != 19-n {
+			t.Errorf("(%v != %v) or (%v != %v)", err, container.ErrDataExists, bt.Size(), 19-n)
+		}
+		if itf, err := bt.Search(25); err != nil || itf != other {
+			t.Errorf("(%v != nil) or (%v != %v)", err, itf, other)
+		}
+		if _, err := bt.Search(id); err != container.ErrNotExist {
+			t.Errorf("%v != %v", err, container.ErrNotExist)
+		}
+		if err := bt.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+}
+
+func TestBTreeMinMaxRange(t *testing.T) {
+	bt := btree.NewBTree(2)
+	if _, err := bt.Min(); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+	if _, err := bt.Max(); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+
+	for _, id := range r.Perm(50) {
+		bt.Insert(&testdata.Corp{ID: id * 2})
+	}
+	if itf, err := bt.Min(); err != nil || itf.(*testdata.Corp).ID != 0 {
+		t.Errorf("(%v != nil) or (%v != 0)", err, itf)
+	}
+	if itf, err := bt.Max(); err != nil || itf.(*testdata.Corp).ID != 98 {
+		t.Errorf("(%v != nil) or (%v != 98)", err, itf)
+	}
+
+	for lo := -2; lo <= 100; lo++ {
+		for hi := lo - 1; hi <= 101; hi += 7 {
+			var got, want []int
+			bt.Range(lo, hi, func(data interface{}) bool {
+				got = append(got, data.(*testdata.Corp).ID)
+				return true
+			})
+			for id := 0; id < 100; id += 2 {
+				if id >= lo && id <= hi {
+					want = append(want, id)
+				}
+			}
+			if !compareIntSlice(got, want) {
+				t.Errorf("Range(%v, %v): %v != %v", lo, hi, got, want)
+			}
+		}
+	}
+
+	var got []int
+	bt.Range(10, 90, func(data interface{}) bool {
+		got = append(got, data.(*testdata.Corp).ID)
+		return len(got) < 3
+	})
+	if !compareIntSlice(got, []int{10, 12, 14}) {
+		t.Errorf("%v != [10 12 14]", got)
+	}
+}
+
+func TestBTreeTraversal(t *testing.T) {
+	bt := createBTree(2, r.Perm(100))
+	id := 0
+	for itf := range bt.Traversal(tree.InorderTrav) {
+		if itf.(*testdata.Corp).ID != id {
+			t.Errorf("%v != %v", itf, id)
+		}
+		id++
+	}
+	if id != 100 {
+		t.Errorf("%v != 100", id)
+	}
+
+	for _, travType := range []tree.TraversalType{tree.PreorderTrav, tree.PostorderTrav, tree.LevelTrav} {
+		ch := bt.Traversal(travType)
+		if itf := <-ch; itf != container.ErrNotSupported {
+			t.Errorf("%v != %v", itf, container.ErrNotSupported)
+		}
+		if _, ok := <-ch; ok {
+			t.Errorf("channel is not closed")
+		}
+	}
+
+	ch := bt.Traversal(tree.MorrisInorderTrav)
+	<-ch
+	bt.Delete(50)
+	var last interface{}
+	for itf := range ch {
+		last = itf
+	}
+	if last != container.ErrConcurrentModification {
+		t.Errorf("%v != %v", last, container.ErrConcurrentModification)
+	}
+}
+
+func TestBTreeFromSorted(t *testing.T) {
+	for _, degree := range []int{2, 3, 4, 16} {
+		for n := 0; n < 300; n++ {
+			seq := make([]container.Interface, n)
+			ids := make([]int, n)
+			for i := range seq {
+				seq[i] = &testdata.Corp{ID: i}
+				ids[i] = i
+			}
+
+			bt, err := btree.FromSorted(seq, degree)
+			if err != nil {
+				t.Fatalf("%v != nil", err)
+			}
+			if err = bt.Validate(); err != nil {
+				t.Fatalf("degree %v, %v items: %v != nil", degree, n, err)
+			}
+			if got := idsOf(bt); !compareIntSlice(got, ids) {
+				t.Fatalf("%v != %v", got, ids)
+			}
+		}
+	}
+
+	seq := []container.Interface{&testdata.Corp{ID: 1}, &testdata.Corp{ID: 3}, &testdata.Corp{ID: 2}}
+	if _, err := btree.FromSorted(seq, 2); err != container.ErrInvalidTraversal {
+		t.Errorf("%v != %v", err, container.ErrInvalidTraversal)
+	}
+	seq = []container.Interface{&testdata.Corp{ID: 1}, &testdata.Corp{ID: 1}}
+	if _, err := btree.FromSorted(seq, 2); err != container.ErrInvalidTraversal {
+		t.Errorf("%v != %v", err, container.ErrInvalidTraversal)
+	}
+
+	// The bulk loaded tree is a regular tree.
+	seq = make([]container.Interface, 1000)
+	for i := range seq {
+		seq[i] = &testdata.Corp{ID: i}
+	}
+	bt, _ := btree.FromSorted(seq, 3)
+	for _, id := range r.Perm(1000)[:500] {
+		bt.Delete(id)
+	}
+	bt.Insert(&testdata.Corp{ID: 1000})
+	if err := bt.Validate(); err != nil || bt.Size() != 501 {
+		t.Errorf("(%v != nil) or (%v != 501)", err, bt.Size())
+	}
+}
+
+func TestBTreeReset(t *testing.T) {
+	bt := createBTree(4, r.Perm(100))
+	if bt.Height() < 1 || bt.Degree() != 4 {
+		t.Errorf("%v < 1 or %v != 4", bt.Height(), bt.Degree())
+	}
+	bt.Reset()
+	if !bt.Empty() || bt.Size() != 0 || bt.Height() != -1 {
+		t.Errorf("tree is not empty")
+	}
+	if btree.NewBTree(0).Degree() != btree.DefaultDegree {
+		t.Errorf("%v != %v", btree.NewBTree(0).Degree(), btree.DefaultDegree)
+	}
+}
@@ -0,0 +1,69 @@
+package btree
+
+import "github.com/NzKSO/container"
+
+// FromSorted builds a B-tree of minimum degree degree, or DefaultDegree if degree is less than 2,
+// from seq in O(n) without comparing its data while building. seq must be the inorder traversal
+// of the tree, that is ordered by Less without duplicates, otherwise returns ErrInvalidTraversal.
+// Leaves are filled almost completely, so that the tree is as shallow as possible.
+func FromSorted(seq []container.Interface, degree int) (*BTree, error) {
+	for i := 1; i < len(seq); i++ {
+		if seq[i-1].Find(seq[i]) || !seq[i-1].Less(seq[i]) {
+			return nil, container.ErrInvalidTraversal
+		}
+	}
+
+	bt := NewBTree(degree)
+	if len(seq) == 0 {
+		return bt, nil
+	}
+	t := bt.degree
+
+	// Every leaf but the last is followed by the item separating it from the next one, so k leaves
+	// hold n-(k-1) items. Spreading them evenly over k = ceil((n+1)/2t) leaves gives each leaf at
+	// least t-1 items once n >= 2t-1, otherwise the only leaf is the root.
+	n := len(seq)
+	k := (n + 2*t) / (2 * t)
+	nodes := make([]*node, 0, k)
+	seps := make([]container.Interface, 0, k-1)
+	for j, pos := 0, 0; j < k; j++ {
+		size := (n - k + 1) / k
+		if j < (n-k+1)%k {
+			size++
+		}
+		nodes = append(nodes, &node{items: append([]container.Interface(nil), seq[pos:pos+size]...)})
+		pos += size
+		if j < k-1 {
+			seps = append(seps, seq[pos])
+			pos++
+		}
+	}
+
+	// Group the nodes of every level under ceil(m/2t) parents, each of which gets between t and 2t
+	// children once m > 2t, and keeps the separators between its children.
+	for len(nodes) > 1 {
+		m := len(nodes)
+		p := (m + 2*t - 1) / (2 * t)
+		parents := make([]*node, 0, p)
+		parentSeps := make([]container.Interface, 0, p-1)
+		for j, pos := 0, 0; j < p; j++ {
+			size := m / p
+			if j < m%p {
+				size++
+			}
+			parents = append(parents, &node{
+				items:    append([]container.Interface(nil), seps[pos:pos+size-1]...),
+				children: append([]*node(nil), nodes[pos:pos+size]...),
+			})
+			pos += size
+			if j < p-1 {
+				parentSeps = append(parentSeps, seps[pos-1])
+			}
+		}
+		nodes, seps = parents, parentSeps
+	}
+
+	bt.root = nodes[0]
+	bt.size = n
+	return bt, nil
+}
@@ -0,0 +1,130 @@
+package cache
+
+// arc implements the Adaptive Replacement Cache of Megiddo and Modha, generalized from counts
+// to costs. Entries used once since they were cached are kept in t1 and entries used more often
+// in t2, while b1 and b2 keep ghost entries, only the keys and costs of entries recently evicted
+// from t1 and t2 respectively. A ghost hit in b1 means that t1 should have been larger, and one
+// in b2 that t2 should have been, so the target cost p of t1 moves accordingly and the policy
+// adapts between recency and frequency. The ghosts are bounded so that t1 and b1 together and
+// all four lists together cost at most the capacity and twice the capacity respectively.
+type arc struct {
+	capacity       int64
+	p              int64
+	t1, t2, b1, b2 entryList
+	ghosts         map[interface{}]*entry
+
+	// Whether the entry being admitted was a ghost in b1 or in b2.
+	fromB1, fromB2 bool
+}
+
+// NewARC returns an empty cache of capacity that evicts entries by the Adaptive Replacement
+// Cache policy, which balances between evicting the least recently used entry and the least
+// frequently used one based on which of them would have been hit again. It remembers as many
+// keys of evicted entries as fit in the capacity besides the cached entries. It panics if
+// capacity is less than 1.
+func NewARC(capacity int64, opts ...Option) *Cache {
+	return newCache(capacity, func(capacity int64) policy {
+		p := &arc{capacity: capacity}
+		p.reset()
+		return p
+	}, opts)
+}
+
+// ratio returns max(a/b, 1) for the costs of ghost lists, which is the step p moves by per unit
+// of cost admitted.
+func ratio(a, b int64) int64 {
+	if b == 0 || a < b {
+		return 1
+	}
+	return a / b
+}
+
+func (p *arc) dropGhost(g *entry) {
+	g.list.remove(g)
+	delete(p.ghosts, g.key)
+}
+
+func (p *arc) admit(e *entry) {
+	g := p.ghosts[e.key]
+	if g == nil {
+		return
+	}
+
+	switch g.list {
+	case &p.b1:
+		p.fromB1 = true
+		p.p += ratio(p.b2.cost, p.b1.cost) * e.cost
+		if p.p > p.capacity {
+			p.p = p.capacity
+		}
+	case &p.b2:
+		p.fromB2 = true
+		p.p -= ratio(p.b1.cost, p.b2.cost) * e.cost
+		if p.p < 0 {
+			p.p = 0
+		}
+	}
+	p.dropGhost(g)
+}
+
+func (p *arc) insert(e *entry) {
+	if p.fromB1 || p.fromB2 {
+		p.t2.pushFront(e)
+	} else {
+		p.t1.pushFront(e)
+	}
+	p.fromB1, p.fromB2 = false, false
+
+	for p.t1.cost+p.b1.cost > p.capacity && !p.b1.empty() {
+		p.dropGhost(p.b1.back())
+	}
+	for p.t1.cost+p.t2.cost+p.b1.cost+p.b2.cost > 2*p.capacity {
+		if !p.b2.empty() {
+			p.dropGhost(p.b2.back())
+		} else {
+			p.dropGhost(p.b1.back())
+		}
+	}
+}
+
+func (p *arc) hit(e *entry) {
+	p.t2.moveToFront(e)
+}
+
+// victim takes the least recently used entry of t1 if t1 exceeds its target, or of t2 otherwise.
+func (p *arc) victim(skip *entry) *entry {
+	first, second := &p.t2, &p.t1
+	if !p.t1.empty() && (p.t1.cost > p.p || p.fromB2 && p.t1.cost == p.p || p.t2.empty()) {
+		first, second = second, first
+	}
+	if e := first.lru(skip); e != nil {
+		return e
+	}
+	return second.lru(skip)
+}
+
+func (p *arc) evicted(e *entry) {
+	ghosts := &p.b1
+	if e.list == &p.t2 {
+		ghosts = &p.b2
+	}
+	e.list.remove(e)
+
+	g := &entry{key: e.key, cost: e.cost}
+	ghosts.pushFront(g)
+	p.ghosts[g.key] = g
+}
+
+func (p *arc) removed(e *entry) {
+	e.list.remove(e)
+}
+
+func (p *arc) reset() {
+	p.p = 0
+	p.t1.init()
+	p.t2.init()
+	p.b1.init()
+	p.b2.init()
+	p.ghosts = make(map[interface{}]*entry)
+	p.fromB1, p.fromB2 = false, false
+}
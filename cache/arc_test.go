@@ -0,0 +1,60 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container/cache"
+)
+
+// replay gets every key of keys from c, setting it on a miss, and returns the hit ratio.
+func replay(c *cache.Cache, keys []int) float64 {
+	for _, key := range keys {
+		if _, err := c.Get(key); err != nil {
+			c.Set(key, key)
+		}
+	}
+	return c.Stats().HitRatio()
+}
+
+func TestCacheARCScan(t *testing.T) {
+	// A small hot set used over and over, interrupted by long scans of keys used only once,
+	// which flush an LRU cache but not an ARC one.
+	var keys []int
+	scan := 1000
+	for round := 0; round < 50; round++ {
+		for i := 0; i < 5; i++ {
+			for key := 0; key < 50; key++ {
+				keys = append(keys, key)
+			}
+		}
+		for i := 0; i < 200; i++ {
+			keys = append(keys, scan)
+			scan++
+		}
+	}
+
+	lru := replay(cache.NewLRU(100), keys)
+	arc := replay(cache.NewARC(100), keys)
+	if arc <= lru {
+		t.Errorf("ARC hit ratio %v <= LRU hit ratio %v", arc, lru)
+	}
+}
+
+func TestCacheARCAdapt(t *testing.T) {
+	// A working set used many times, followed by another one of the same size. LFU sticks to
+	// the first set and keeps evicting the second one, ARC moves on to the second set.
+	var keys []int
+	for _, base := range []int{0, 100} {
+		for round := 0; round < 50; round++ {
+			for key := base; key < base+8; key++ {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	lfu := replay(cache.NewLFU(10), keys)
+	arc := replay(cache.NewARC(10), keys)
+	if arc <= lfu {
+		t.Errorf("ARC hit ratio %v <= LFU hit ratio %v", arc, lfu)
+	}
+}
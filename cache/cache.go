@@ -0,0 +1,361 @@
+// Package cache implements in-memory caches bounded by capacity under the LRU, LFU and ARC
+// replacement policies, with optional costs per entry, expiry and eviction callbacks.
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NzKSO/container"
+)
+
+// policy decides which entry a Cache evicts when it's full. The cache keeps the entries in a
+// map and calls these hooks with its lock held, which keep them in the lists of the policy.
+type policy interface {
+	// admit is called on a new entry before room is made for it, and insert after it.
+	admit(e *entry)
+	insert(e *entry)
+	// hit is called when e is read or replaced.
+	hit(e *entry)
+	// victim returns the entry to evict next other than skip, which is nil unless skip is
+	// being replaced.
+	victim(skip *entry) *entry
+	// evicted is called when e is evicted for room, removed when it's deleted or expires.
+	evicted(e *entry)
+	removed(e *entry)
+	reset()
+}
+
+// EvictReason tells why an entry was evicted.
+type EvictReason int
+
+// These constants respectively denote an entry evicted to make room for another one, and an
+// entry dropped because it expired.
+const (
+	Evicted EvictReason = iota
+	Expired
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case Evicted:
+		return "evicted"
+	case Expired:
+		return "expired"
+	}
+	return fmt.Sprintf("EvictReason(%d)", int(r))
+}
+
+// Stats counts the lookups and evictions of a cache.
+type Stats struct {
+	Hits, Misses uint64
+	Evictions    uint64 // entries evicted for room
+	Expirations  uint64 // entries dropped because they expired
+}
+
+// HitRatio returns the ratio of hits to lookups, or 0 if there were none.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Option configures a Cache.
+type Option func(c *Cache)
+
+// WithCost makes the cache measure its capacity in the cost of every entry as returned by cost,
+// which must not be negative, rather than in the number of entries.
+func WithCost(cost func(key, value interface{}) int64) Option {
+	return func(c *Cache) {
+		c.costOf = cost
+	}
+}
+
+// WithTTL makes entries set by Set expire ttl after they are set. A ttl less than or equal to 0
+// means that they never expire, which is the default.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// WithClock makes the cache tell the time by now instead of time.Now, which is meant for tests.
+func WithClock(now func() time.Time) Option {
+	return func(c *Cache) {
+		c.now = now
+	}
+}
+
+// WithOnEvict makes the cache call onEvict with every entry it evicts for room or drops because
+// it expired, but not with entries deleted by Delete or Reset. onEvict is called after the cache
+// is unlocked, in the goroutine that caused the eviction, so it may use the cache.
+func WithOnEvict(onEvict func(key, value interface{}, reason EvictReason)) Option {
+	return func(c *Cache) {
+		c.onEvict = onEvict
+	}
+}
+
+// eviction records an evicted entry until onEvict is called with it.
+type eviction struct {
+	key, value interface{}
+	reason     EvictReason
+}
+
+// Cache represents a cache of values by keys, which must be comparable as map keys are. It
+// holds entries of total cost up to its capacity, every entry costing 1 unless WithCost is
+// given, and evicts entries chosen by its replacement policy to make room for new ones. Expired
+// entries are dropped when they are looked up or chosen for eviction, or by RemoveExpired. Cache
+// is safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	items    map[interface{}]*entry
+	policy   policy
+	capacity int64
+	cost     int64
+	stats    Stats
+
+	costOf  func(key, value interface{}) int64
+	ttl     time.Duration
+	now     func() time.Time
+	onEvict func(key, value interface{}, reason EvictReason)
+}
+
+func newCache(capacity int64, p func(capacity int64) policy, opts []Option) *Cache {
+	if capacity < 1 {
+		panic(fmt.Sprintf("cache: capacity %d", capacity))
+	}
+
+	c := &Cache{
+		items:    make(map[interface{}]*entry),
+		policy:   p(capacity),
+		capacity: capacity,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Capacity returns the capacity of the cache.
+func (c *Cache) Capacity() int64 {
+	return c.capacity
+}
+
+// Len returns the number of entries in the cache, including expired ones not dropped yet.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
+
+// Cost returns the total cost of the entries in the cache.
+func (c *Cache) Cost() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cost
+}
+
+// Stats returns the statistics of the cache since it was created or Reset.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+func (c *Cache) expired(e *entry) bool {
+	return !e.expires.IsZero() && !c.now().Before(e.expires)
+}
+
+// notify calls onEvict with evs, the cache must not be locked.
+func (c *Cache) notify(evs []eviction) {
+	if c.onEvict == nil {
+		return
+	}
+	for _, ev := range evs {
+		c.onEvict(ev.key, ev.value, ev.reason)
+	}
+}
+
+// drop removes e from the cache. If e is being evicted for room, the policy is told so unless e
+// expired, and the eviction is appended to evs; otherwise e is just deleted.
+func (c *Cache) drop(e *entry, evict bool, evs *[]eviction) {
+	delete(c.items, e.key)
+	c.cost -= e.cost
+
+	switch {
+	case c.expired(e):
+		c.policy.removed(e)
+		c.stats.Expirations++
+		*evs = append(*evs, eviction{e.key, e.value, Expired})
+	case evict:
+		c.policy.evicted(e)
+		c.stats.Evictions++
+		*evs = append(*evs, eviction{e.key, e.value, Evicted})
+	default:
+		c.policy.removed(e)
+	}
+}
+
+// lookup returns the entry of key, dropping it if it expired.
+func (c *Cache) lookup(key interface{}, evs *[]eviction) *entry {
+	e := c.items[key]
+	if e != nil && c.expired(e) {
+		c.drop(e, false, evs)
+		return nil
+	}
+	return e
+}
+
+// Get returns the value of key and counts it as used. If key isn't cached or expired, returns
+// ErrNotExist. Either way the lookup is counted in Stats.
+func (c *Cache) Get(key interface{}) (interface{}, error) {
+	var evs []eviction
+	defer func() { c.notify(evs) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.lookup(key, &evs)
+	if e == nil {
+		c.stats.Misses++
+		return nil, container.ErrNotExist
+	}
+	c.stats.Hits++
+	c.policy.hit(e)
+	return e.value, nil
+}
+
+// Peek returns the value of key without counting it as used or counting the lookup. If key
+// isn't cached or expired, returns ErrNotExist.
+func (c *Cache) Peek(key interface{}) (interface{}, error) {
+	var evs []eviction
+	defer func() { c.notify(evs) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e := c.lookup(key, &evs); e != nil {
+		return e.value, nil
+	}
+	return nil, container.ErrNotExist
+}
+
+// Set sets the value of key, which expires after the TTL given by WithTTL if any, and counts it
+// as used. See SetWithTTL.
+func (c *Cache) Set(key, value interface{}) error {
+	return c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL sets the value of key, which expires ttl later unless ttl is less than or equal
+// to 0, and counts it as used. Entries are evicted until there is room for it. If it costs more
+// than the capacity of the cache, returns ErrTooLarge and leaves the cache unchanged.
+func (c *Cache) SetWithTTL(key, value interface{}, ttl time.Duration) error {
+	cost := int64(1)
+	if c.costOf != nil {
+		cost = c.costOf(key, value)
+	}
+	if cost > c.capacity {
+		return container.ErrTooLarge
+	}
+
+	var evs []eviction
+	defer func() { c.notify(evs) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	if e := c.lookup(key, &evs); e != nil {
+		e.value, e.expires = value, expires
+		c.cost += cost - e.cost
+		e.list.cost += cost - e.cost
+		e.cost = cost
+		c.policy.hit(e)
+		for c.cost > c.capacity {
+			c.drop(c.policy.victim(e), true, &evs)
+		}
+		return nil
+	}
+
+	e := &entry{key: key, value: value, cost: cost, expires: expires}
+	c.policy.admit(e)
+	for c.cost+cost > c.capacity {
+		c.drop(c.policy.victim(nil), true, &evs)
+	}
+	c.items[key] = e
+	c.cost += cost
+	c.policy.insert(e)
+	return nil
+}
+
+// Contains reports whether key is cached and not expired, without counting it as used.
+func (c *Cache) Contains(key interface{}) bool {
+	_, err := c.Peek(key)
+	return err == nil
+}
+
+// Delete deletes key from the cache. If key isn't cached or expired, returns ErrNotExist.
+func (c *Cache) Delete(key interface{}) error {
+	var evs []eviction
+	defer func() { c.notify(evs) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.lookup(key, &evs)
+	if e == nil {
+		return container.ErrNotExist
+	}
+	c.drop(e, false, &evs)
+	return nil
+}
+
+// RemoveExpired drops all expired entries in O(n) time and returns how many there were.
+func (c *Cache) RemoveExpired() int {
+	var evs []eviction
+	defer func() { c.notify(evs) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.items {
+		if c.expired(e) {
+			c.drop(e, false, &evs)
+		}
+	}
+	return len(evs)
+}
+
+// Keys returns the keys of all entries in the cache in no particular order, including expired
+// ones not dropped yet.
+func (c *Cache) Keys() []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]interface{}, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Reset drops all entries in the cache without calling onEvict, and clears its statistics.
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[interface{}]*entry)
+	c.cost = 0
+	c.stats = Stats{}
+	c.policy.reset()
+}
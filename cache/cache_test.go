@@ -0,0 +1,251 @@
+package cache_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/cache"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+var constructors = map[string]func(capacity int64, opts ...cache.Option) *cache.Cache{
+	"LRU": cache.NewLRU,
+	"LFU": cache.NewLFU,
+	"ARC": cache.NewARC,
+}
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	fc.now = fc.now.Add(d)
+	fc.mu.Unlock()
+}
+
+func TestCacheBasic(t *testing.T) {
+	for name, newCache := range constructors {
+		c := newCache(3)
+		if _, err := c.Get("a"); err != container.ErrNotExist {
+			t.Errorf("%v: %v != %v", name, err, container.ErrNotExist)
+		}
+
+		for i, key := range []string{"a", "b", "c"} {
+			if err := c.Set(key, i); err != nil {
+				t.Errorf("%v: %v != nil", name, err)
+			}
+		}
+		if v, err := c.Get("b"); v != 1 || err != nil {
+			t.Errorf("%v: (%v != 1) or (%v != nil)", name, v, err)
+		}
+		c.Set("b", 10)
+		if v, err := c.Peek("b"); v != 10 || err != nil {
+			t.Errorf("%v: (%v != 10) or (%v != nil)", name, v, err)
+		}
+		if c.Len() != 3 || c.Cost() != 3 || len(c.Keys()) != 3 {
+			t.Errorf("%v: (%v != 3) or (%v != 3)", name, c.Len(), c.Cost())
+		}
+
+		c.Set("d", 3)
+		if c.Len() != 3 || !c.Contains("b") || !c.Contains("d") {
+			t.Errorf("%v: %v != 3", name, c.Len())
+		}
+		if err := c.Delete("d"); err != nil || c.Contains("d") {
+			t.Errorf("%v: %v != nil", name, err)
+		}
+		if err := c.Delete("d"); err != container.ErrNotExist {
+			t.Errorf("%v: %v != %v", name, err, container.ErrNotExist)
+		}
+
+		st := c.Stats()
+		if st.Hits != 1 || st.Misses != 1 || st.Evictions != 1 || st.HitRatio() != 0.5 {
+			t.Errorf("%v: %+v", name, st)
+		}
+
+		c.Reset()
+		if c.Len() != 0 || c.Cost() != 0 || c.Stats() != (cache.Stats{}) || c.Contains("b") {
+			t.Errorf("%v: cache is not empty", name)
+		}
+	}
+}
+
+func TestCacheLRU(t *testing.T) {
+	var evicted []interface{}
+	c := cache.NewLRU(3, cache.WithOnEvict(func(key, value interface{}, reason cache.EvictReason) {
+		if reason != cache.Evicted {
+			t.Errorf("%v != %v", reason, cache.Evicted)
+		}
+		evicted = append(evicted, key)
+	}))
+
+	for _, key := range []int{1, 2, 3} {
+		c.Set(key, key)
+	}
+	c.Get(1)
+	c.Set(4, 4) // evicts 2
+	c.Peek(3)
+	c.Set(5, 5) // evicts 3, Peek doesn't count as use
+	c.Set(1, 1)
+	c.Set(6, 6) // evicts 4
+	if len(evicted) != 3 || evicted[0] != 2 || evicted[1] != 3 || evicted[2] != 4 {
+		t.Errorf("%v != [2 3 4]", evicted)
+	}
+}
+
+func TestCacheCost(t *testing.T) {
+	for name, newCache := range constructors {
+		var evictedCost int64
+		cost := func(key, value interface{}) int64 { return int64(len(value.(string))) }
+		c := newCache(10, cache.WithCost(cost), cache.WithOnEvict(func(key, value interface{}, reason cache.EvictReason) {
+			evictedCost += cost(key, value)
+		}))
+
+		if err := c.Set("x", "01234567890"); err != container.ErrTooLarge || c.Len() != 0 {
+			t.Errorf("%v: %v != %v", name, err, container.ErrTooLarge)
+		}
+
+		var total int64
+		for i := 0; i < 500; i++ {
+			key := r.Intn(20)
+			value := string(make([]byte, r.Intn(6)))
+			if old, err := c.Peek(key); err == nil {
+				total -= int64(len(old.(string)))
+			}
+			total += int64(len(value))
+
+			if err := c.Set(key, value); err != nil {
+				t.Fatalf("%v: %v != nil", name, err)
+			}
+			if v, err := c.Peek(key); err != nil || v != value {
+				t.Fatalf("%v: just set %v is not cached", name, key)
+			}
+			if r.Intn(2) == 0 {
+				c.Get(r.Intn(20))
+			}
+
+			if c.Cost() > c.Capacity() || c.Cost() != total-evictedCost {
+				t.Fatalf("%v: (%v > %v) or (%v != %v)", name, c.Cost(), c.Capacity(), c.Cost(), total-evictedCost)
+			}
+		}
+
+		var sum int64
+		for _, key := range c.Keys() {
+			v, _ := c.Peek(key)
+			sum += int64(len(v.(string)))
+		}
+		if sum != c.Cost() {
+			t.Errorf("%v: %v != %v", name, sum, c.Cost())
+		}
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	for name, newCache := range constructors {
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		expired := make(map[interface{}]bool)
+		c := newCache(10, cache.WithTTL(time.Minute), cache.WithClock(clock.Now),
+			cache.WithOnEvict(func(key, value interface{}, reason cache.EvictReason) {
+				if reason != cache.Expired {
+					t.Errorf("%v: %v != %v", name, reason, cache.Expired)
+				}
+				expired[key] = true
+			}))
+
+		c.Set("a", 1)
+		c.SetWithTTL("b", 2, 2*time.Minute)
+		c.SetWithTTL("c", 3, 0)
+		c.SetWithTTL("d", 4, 2*time.Minute)
+		clock.Advance(time.Minute - 1)
+		if !c.Contains("a") || len(expired) != 0 {
+			t.Errorf("%v: a expired early", name)
+		}
+
+		clock.Advance(1)
+		if _, err := c.Get("a"); err != container.ErrNotExist || !expired["a"] {
+			t.Errorf("%v: %v != %v", name, err, container.ErrNotExist)
+		}
+		c.Set("d", 5) // d is replaced and lives only a minute from now
+		if n := c.RemoveExpired(); n != 0 || c.Len() != 3 {
+			t.Errorf("%v: (%v != 0) or (%v != 3)", name, n, c.Len())
+		}
+
+		clock.Advance(time.Hour)
+		if n := c.RemoveExpired(); n != 2 || !expired["b"] || !expired["d"] {
+			t.Errorf("%v: %v != 2", name, n)
+		}
+		if v, err := c.Get("c"); v != 3 || err != nil {
+			t.Errorf("%v: (%v != 3) or (%v != nil)", name, v, err)
+		}
+
+		st := c.Stats()
+		if st.Expirations != 3 || st.Evictions != 0 || st.Hits != 1 || st.Misses != 1 {
+			t.Errorf("%v: %+v", name, st)
+		}
+
+		// Expired entries are dropped first when making room.
+		c.Reset()
+		for i := 0; i < 10; i++ {
+			c.Set(i, i)
+		}
+		clock.Advance(time.Hour)
+		c.Set(10, 10)
+		if st := c.Stats(); st.Expirations != 1 || st.Evictions != 0 {
+			t.Errorf("%v: %+v", name, st)
+		}
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	for name, newCache := range constructors {
+		var evictions int
+		var c *cache.Cache
+		c = newCache(64, cache.WithOnEvict(func(key, value interface{}, reason cache.EvictReason) {
+			// The cache is unlocked while onEvict runs.
+			c.Contains(key)
+			evictions++
+		}))
+
+		var wg sync.WaitGroup
+		// Set is serialized so that onEvict needn't lock evictions, Get evicts nothing here.
+		var mu sync.Mutex
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(seed int64) {
+				defer wg.Done()
+				rnd := rand.New(rand.NewSource(seed))
+				for i := 0; i < 2000; i++ {
+					key := rnd.Intn(200)
+					if rnd.Intn(2) == 0 {
+						mu.Lock()
+						c.Set(key, key)
+						mu.Unlock()
+					} else if v, err := c.Get(key); err == nil && v != key {
+						t.Errorf("%v: %v != %v", name, v, key)
+					}
+				}
+			}(r.Int63())
+		}
+		wg.Wait()
+
+		st := c.Stats()
+		if c.Len() > 64 || st.Hits+st.Misses > 8*2000 {
+			t.Errorf("(%v: %v > 64) or (%+v)", name, c.Len(), st)
+		}
+		if uint64(evictions) != st.Evictions {
+			t.Errorf("%v: %v != %v", name, evictions, st.Evictions)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package cache
+
+// bucket holds the entries used freq times, most recently used first. Buckets are linked in
+// ascending order of freq, only those holding any entry being kept.
+type bucket struct {
+	freq       int
+	entries    entryList
+	prev, next *bucket
+}
+
+// lfu evicts the least frequently used entry, and the least recently used one among those used
+// equally often. All of its operations take O(1) time, since an entry used once more only moves
+// from its bucket to the next one.
+type lfu struct {
+	root bucket // the sentinel of the ring of buckets
+}
+
+// NewLFU returns an empty cache of capacity that evicts the least frequently used entry first,
+// every Get or Set of an entry counting as a use. It panics if capacity is less than 1.
+func NewLFU(capacity int64, opts ...Option) *Cache {
+	return newCache(capacity, func(int64) policy {
+		p := &lfu{}
+		p.reset()
+		return p
+	}, opts)
+}
+
+// bucketAfter returns the bucket of freq right after b, creating it if it doesn't exist.
+func (p *lfu) bucketAfter(b *bucket, freq int) *bucket {
+	if next := b.next; next != &p.root && next.freq == freq {
+		return next
+	}
+
+	nb := &bucket{freq: freq, prev: b, next: b.next}
+	nb.entries.init()
+	b.next.prev, b.next = nb, nb
+	return nb
+}
+
+// detach removes e from its bucket, and the bucket if it becomes empty.
+func (p *lfu) detach(e *entry) {
+	b := e.bucket
+	b.entries.remove(e)
+	e.bucket = nil
+	if b.entries.empty() {
+		b.prev.next, b.next.prev = b.next, b.prev
+	}
+}
+
+func (p *lfu) admit(e *entry) {}
+
+func (p *lfu) insert(e *entry) {
+	b := p.bucketAfter(&p.root, 1)
+	b.entries.pushFront(e)
+	e.bucket = b
+}
+
+func (p *lfu) hit(e *entry) {
+	b := e.bucket
+	nb := p.bucketAfter(b, b.freq+1)
+	p.detach(e)
+	nb.entries.pushFront(e)
+	e.bucket = nb
+}
+
+func (p *lfu) victim(skip *entry) *entry {
+	for b := p.root.next; b != &p.root; b = b.next {
+		if e := b.entries.lru(skip); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (p *lfu) evicted(e *entry) {
+	p.detach(e)
+}
+
+func (p *lfu) removed(e *entry) {
+	p.detach(e)
+}
+
+func (p *lfu) reset() {
+	p.root.prev, p.root.next = &p.root, &p.root
+}
@@ -0,0 +1,53 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container/cache"
+)
+
+func TestCacheLFU(t *testing.T) {
+	var evicted []interface{}
+	c := cache.NewLFU(3, cache.WithOnEvict(func(key, value interface{}, reason cache.EvictReason) {
+		evicted = append(evicted, key)
+	}))
+
+	for _, key := range []int{1, 2, 3} {
+		c.Set(key, key)
+	}
+	c.Get(1)
+	c.Get(1)
+	c.Get(2)
+	c.Get(3)
+	c.Set(4, 4) // 2 and 3 were used twice, 2 less recently
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("%v != [2]", evicted)
+	}
+
+	c.Set(5, 5) // 4 was used once
+	c.Get(5)
+	c.Get(5)
+	c.Get(5)
+	c.Set(6, 6) // 3 was used twice
+	c.Set(7, 7) // 6 was used once
+	if len(evicted) != 4 || evicted[1] != 4 || evicted[2] != 3 || evicted[3] != 6 {
+		t.Errorf("%v != [2 4 3 6]", evicted)
+	}
+	for _, key := range []int{1, 5, 7} {
+		if !c.Contains(key) {
+			t.Errorf("%v is not cached", key)
+		}
+	}
+
+	// A replaced entry is never its own victim, even if it's the least frequently used one.
+	c = cache.NewLFU(4, cache.WithCost(func(key, value interface{}) int64 { return value.(int64) }))
+	c.Set("a", int64(1))
+	c.Set("b", int64(1))
+	c.Get("b")
+	c.Set("c", int64(1))
+	c.Get("c")
+	c.Set("a", int64(3))
+	if !c.Contains("a") || c.Len() != 2 || c.Cost() != 4 {
+		t.Errorf("(%v != 2) or (%v != 4)", c.Len(), c.Cost())
+	}
+}
@@ -0,0 +1,79 @@
+package cache
+
+import "time"
+
+// entry is a cached key and value, linked into one of the lists of the policy. Ghost entries
+// of ARC keep only key and cost.
+type entry struct {
+	key, value interface{}
+	cost       int64
+	expires    time.Time // zero if the entry never expires
+
+	prev, next *entry
+	list       *entryList
+	bucket     *bucket // the frequency bucket of LFU
+}
+
+// entryList is a doubly linked list of entries, kept as a ring around a sentinel, with the
+// most recently used entry at the front. It keeps the total cost of its entries.
+type entryList struct {
+	root entry
+	size int
+	cost int64
+}
+
+func (l *entryList) init() *entryList {
+	l.root.prev, l.root.next = &l.root, &l.root
+	l.size, l.cost = 0, 0
+	return l
+}
+
+func (l *entryList) empty() bool {
+	return l.size == 0
+}
+
+// back returns the least recently used entry, or nil if the list is empty.
+func (l *entryList) back() *entry {
+	if l.size == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// before returns the entry in front of e, or nil if e is at the front.
+func (l *entryList) before(e *entry) *entry {
+	if e.prev == &l.root {
+		return nil
+	}
+	return e.prev
+}
+
+func (l *entryList) pushFront(e *entry) {
+	e.prev, e.next = &l.root, l.root.next
+	e.prev.next, e.next.prev = e, e
+	e.list = l
+	l.size++
+	l.cost += e.cost
+}
+
+func (l *entryList) remove(e *entry) {
+	e.prev.next, e.next.prev = e.next, e.prev
+	e.prev, e.next, e.list = nil, nil, nil
+	l.size--
+	l.cost -= e.cost
+}
+
+// moveToFront moves e from whichever list it's in to the front of l.
+func (l *entryList) moveToFront(e *entry) {
+	e.list.remove(e)
+	l.pushFront(e)
+}
+
+// lru returns the least recently used entry of l other than skip, or nil if there is none.
+func (l *entryList) lru(skip *entry) *entry {
+	e := l.back()
+	if e != nil && e == skip {
+		e = l.before(e)
+	}
+	return e
+}
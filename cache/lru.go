@@ -0,0 +1,42 @@
+package cache
+
+// lru evicts the least recently used entry, keeping all entries in a single list.
+type lru struct {
+	entries entryList
+}
+
+// NewLRU returns an empty cache of capacity that evicts the least recently used entry first.
+// It panics if capacity is less than 1.
+func NewLRU(capacity int64, opts ...Option) *Cache {
+	return newCache(capacity, func(int64) policy {
+		p := &lru{}
+		p.entries.init()
+		return p
+	}, opts)
+}
+
+func (p *lru) admit(e *entry) {}
+
+func (p *lru) insert(e *entry) {
+	p.entries.pushFront(e)
+}
+
+func (p *lru) hit(e *entry) {
+	p.entries.moveToFront(e)
+}
+
+func (p *lru) victim(skip *entry) *entry {
+	return p.entries.lru(skip)
+}
+
+func (p *lru) evicted(e *entry) {
+	p.entries.remove(e)
+}
+
+func (p *lru) removed(e *entry) {
+	p.entries.remove(e)
+}
+
+func (p *lru) reset() {
+	p.entries.init()
+}
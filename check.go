@@ -0,0 +1,243 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// These constants name the rules a Checker verifies.
+const (
+	// RuleReflexivity is violated when a value doesn't Find itself.
+	RuleReflexivity = "reflexivity"
+	// RuleSymmetry is violated when a.Find(b) and b.Find(a) disagree.
+	RuleSymmetry = "symmetry"
+	// RuleAntisymmetry is violated when two values that don't Find each other both, or neither, report Less.
+	RuleAntisymmetry = "antisymmetry"
+	// RuleTransitivity is violated when a before b and b before c, but not a before c.
+	RuleTransitivity = "transitivity"
+	// RuleAgreement is violated when two values that Find each other are ordered differently against a third.
+	RuleAgreement = "agreement"
+	// RuleKeyType is violated when a key doesn't implement Interface, such as a plain search key, so
+	// that Less and Find can't be checked against it. Unknown key types are often what an inconsistent
+	// Less gets wrong, so it's reported once for every pair of value type and key type observed.
+	RuleKeyType = "key type"
+)
+
+// Violation describes an inconsistency between Less and Find implementations found by a Checker.
+type Violation struct {
+	Rule   string
+	Values []interface{}
+}
+
+// Error implements the error interface.
+func (v Violation) Error() string {
+	s := v.Rule + " is violated by"
+	for _, val := range v.Values {
+		s += fmt.Sprintf(" %v", val)
+	}
+	return s
+}
+
+// DefaultCheckerWindow is the number of values a Checker keeps when it's given a window less than 2.
+const DefaultCheckerWindow = 64
+
+// Checker verifies that Less and Find are consistent on the values it observes. Comparisons are
+// checked for reflexivity and symmetry of Find, antisymmetry and transitivity of Less, and
+// agreement of Less between values that Find each other. Triples are formed with the values
+// observed most recently, up to the window of the Checker, so that its memory stays bounded
+// however many values it observes. A Checker is safe for concurrent use.
+type Checker struct {
+	mu         sync.Mutex
+	window     int
+	seen       []seenValue // in the order of observation, oldest first
+	next       uint64
+	checked    map[[2]uint64]bool
+	keyTypes   map[[2]reflect.Type]bool
+	violations []Violation
+
+	// OnViolation, if not nil, is called for every violation found, e.g. to panic at the
+	// offending comparison instead of inspecting Violations afterwards.
+	OnViolation func(Violation)
+}
+
+// seenValue is a value observed by a Checker, numbered in the order of observation.
+type seenValue struct {
+	v  Interface
+	id uint64
+}
+
+// NewChecker returns a Checker which has observed nothing and keeps the window values observed
+// most recently, or DefaultCheckerWindow if window is less than 2.
+func NewChecker(window int) *Checker {
+	if window < 2 {
+		window = DefaultCheckerWindow
+	}
+	return &Checker{window: window, checked: make(map[[2]uint64]bool), keyTypes: make(map[[2]reflect.Type]bool)}
+}
+
+// Observe records a comparison of a with key. Only keys that implement Interface themselves
+// can be checked, for others Observe reports a violation of RuleKeyType, unless it was already
+// reported for the same types, and returns ErrNotInterface.
+func (c *Checker) Observe(a Interface, key interface{}) error {
+	var found []Violation
+	b, ok := key.(Interface)
+
+	c.mu.Lock()
+	if ok {
+		c.add(a, nil)
+		j := c.add(b, a)
+		found = c.check(c.index(a), j)
+	} else if types := [2]reflect.Type{reflect.TypeOf(a), reflect.TypeOf(key)}; !c.keyTypes[types] {
+		c.keyTypes[types] = true
+		c.report(RuleKeyType, a, key)
+		found = append(found, Violation{RuleKeyType, []interface{}{a, key}})
+	}
+	c.mu.Unlock()
+
+	if c.OnViolation != nil {
+		for _, v := range found {
+			c.OnViolation(v)
+		}
+	}
+	if !ok {
+		return ErrNotInterface
+	}
+	return nil
+}
+
+// Violations returns the violations found so far, up to the window of the Checker. Later ones
+// are only passed to OnViolation.
+func (c *Checker) Violations() []Violation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Violation(nil), c.violations...)
+}
+
+// index returns the index of v in seen, or -1 if no seen value is identical to it.
+func (c *Checker) index(v Interface) int {
+	for i, s := range c.seen {
+		if s.v == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// add returns the index of v in seen, appending it if no seen value is identical to it. If the
+// window is full, the oldest value other than keep is dropped first, which shifts the indices of
+// the values after it.
+func (c *Checker) add(v, keep Interface) int {
+	if i := c.index(v); i >= 0 {
+		return i
+	}
+
+	if len(c.seen) == c.window {
+		drop := 0
+		if c.seen[0].v == keep {
+			drop = 1
+		}
+		old := c.seen[drop].id
+		for _, s := range c.seen {
+			delete(c.checked, [2]uint64{old, s.id})
+			delete(c.checked, [2]uint64{s.id, old})
+		}
+		c.seen = append(c.seen[:drop], c.seen[drop+1:]...)
+	}
+	c.seen = append(c.seen, seenValue{v, c.next})
+	c.next++
+	if !v.Find(v) {
+		c.report(RuleReflexivity, v)
+	}
+	return len(c.seen) - 1
+}
+
+func (c *Checker) report(rule string, values ...interface{}) {
+	if len(c.violations) < c.window {
+		c.violations = append(c.violations, Violation{rule, values})
+	}
+}
+
+// check verifies the pair of seen values at i and j, including every triple it forms with
+// other seen values, and returns the violations found. Each pair is checked only once.
+func (c *Checker) check(i, j int) []Violation {
+	if i == j {
+		return nil
+	}
+	if i > j {
+		i, j = j, i
+	}
+	pair := [2]uint64{c.seen[i].id, c.seen[j].id}
+	if c.checked[pair] {
+		return nil
+	}
+	c.checked[pair] = true
+
+	var found []Violation
+	report := func(rule string, values ...interface{}) {
+		c.report(rule, values...)
+		found = append(found, Violation{rule, values})
+	}
+	a, b := c.seen[i].v, c.seen[j].v
+
+	fa, fb := a.Find(b), b.Find(a)
+	if fa != fb {
+		report(RuleSymmetry, a, b)
+		return found
+	}
+
+	if fa {
+		for _, sv := range c.seen {
+			s := sv.v
+			if s == a || s == b || s.Find(a) || s.Find(b) {
+				continue
+			}
+			if a.Less(s) != b.Less(s) || s.Less(a) != s.Less(b) {
+				report(RuleAgreement, a, b, s)
+			}
+		}
+		return found
+	}
+
+	la, lb := a.Less(b), b.Less(a)
+	if la == lb {
+		report(RuleAntisymmetry, a, b)
+		return found
+	}
+
+	lo, hi := a, b
+	if !la {
+		lo, hi = b, a
+	}
+	for _, sv := range c.seen {
+		s := sv.v
+		if s == lo || s == hi || s.Find(lo) || s.Find(hi) {
+			continue
+		}
+		if hi.Less(s) && !lo.Less(s) {
+			report(RuleTransitivity, lo, hi, s)
+		}
+		if s.Less(lo) && !s.Less(hi) {
+			report(RuleTransitivity, s, lo, hi)
+		}
+	}
+	return found
+}
+
+// CheckInterface checks Less and Find on every pair and triple of samples, and returns the
+// violations found. A nil result means samples are consistent with each other.
+func CheckInterface(samples []Interface) []Violation {
+	// The window holds all samples, and every violation they can have.
+	c := NewChecker(2 * len(samples) * len(samples) * len(samples))
+	for _, s := range samples {
+		c.add(s, nil)
+	}
+
+	for i := range c.seen {
+		for j := i + 1; j < len(c.seen); j++ {
+			c.check(i, j)
+		}
+	}
+	return c.violations
+}
@@ -0,0 +1,114 @@
+package container_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+)
+
+// rps orders values like rock-paper-scissors, which is antisymmetric but not transitive.
+type rps struct {
+	ID int
+}
+
+func (v *rps) Less(kv interface{}) bool {
+	return (kv.(*rps).ID-v.ID+3)%3 == 1
+}
+
+func (v *rps) Find(key interface{}) bool {
+	return v.ID == key.(*rps).ID
+}
+
+func (v *rps) Set(interface{}) {}
+
+// greedy reports Less for everything, which is not antisymmetric.
+type greedy struct {
+	ID int
+}
+
+func (v *greedy) Less(interface{}) bool {
+	return true
+}
+
+func (v *greedy) Find(key interface{}) bool {
+	return v.ID == key.(*greedy).ID
+}
+
+func (v *greedy) Set(interface{}) {}
+
+func hasRule(violations []container.Violation, rule string) bool {
+	for _, v := range violations {
+		if v.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheckInterface(t *testing.T) {
+	var samples []container.Interface
+	for i := range testdata.TestCases {
+		samples = append(samples, &testdata.TestCases[i])
+	}
+	if violations := container.CheckInterface(samples); violations != nil {
+		t.Errorf("%v != nil", violations)
+	}
+
+	violations := container.CheckInterface([]container.Interface{&rps{0}, &rps{1}, &rps{2}})
+	if !hasRule(violations, container.RuleTransitivity) {
+		t.Errorf("%v doesn't contain %v", violations, container.RuleTransitivity)
+	}
+
+	violations = container.CheckInterface([]container.Interface{&greedy{0}, &greedy{1}})
+	if !hasRule(violations, container.RuleAntisymmetry) {
+		t.Errorf("%v doesn't contain %v", violations, container.RuleAntisymmetry)
+	}
+}
+
+func TestCheckerOnViolation(t *testing.T) {
+	c := container.NewChecker(0)
+	var called int
+	c.OnViolation = func(container.Violation) {
+		called++
+	}
+
+	a, b := &greedy{0}, &greedy{1}
+	c.Observe(a, b)
+	c.Observe(b, a)
+	if err := c.Observe(a, 1); err != container.ErrNotInterface {
+		t.Errorf("%v != %v", err, container.ErrNotInterface)
+	}
+
+	c.Observe(b, 2)
+
+	// The antisymmetry of a and b, and int keys of greedy once.
+	if called != 2 || len(c.Violations()) != 2 {
+		t.Errorf("(%v != 2) or (%v != 2)", called, len(c.Violations()))
+	}
+	if !hasRule(c.Violations(), container.RuleKeyType) {
+		t.Errorf("%v doesn't contain %v", c.Violations(), container.RuleKeyType)
+	}
+}
+
+func TestCheckerWindow(t *testing.T) {
+	c := container.NewChecker(4)
+	for i := 1; i < 1000; i++ {
+		if err := c.Observe(&testdata.Corp{ID: i - 1}, &testdata.Corp{ID: i}); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+	if violations := c.Violations(); violations != nil {
+		t.Errorf("%v != nil", violations)
+	}
+
+	// A window just large enough for the values of a violation still finds it.
+	c = container.NewChecker(3)
+	a, b, d := &rps{0}, &rps{1}, &rps{2}
+	c.Observe(a, b)
+	c.Observe(b, d)
+	c.Observe(d, a)
+	if !hasRule(c.Violations(), container.RuleTransitivity) {
+		t.Errorf("%v doesn't contain %v", c.Violations(), container.RuleTransitivity)
+	}
+}
@@ -1,7 +1,10 @@
 // Package container defined some variables and interfaces related to container library.
 package container
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Lesser implements how data to be compared.
 type Lesser interface {
@@ -38,6 +41,10 @@ type Interface interface {
 	Setter
 }
 
+// Copier returns a copy of data that doesn't share anything Set could modify with data. It's used
+// by the Clone methods of containers to keep their payloads from being shared.
+type Copier func(data Interface) Interface
+
 var (
 	// ErrNotExist means that The data to found by key doesn't exist.
 	ErrNotExist = errors.New("data doesn't exist")
@@ -50,4 +57,75 @@ var (
 
 	// ErrEmptyTree means that the tree is empty.
 	ErrEmptyTree = errors.New("Tree is empty")
+
+	// ErrNotAugmented means that the container keeps no aggregate to be queried.
+	ErrNotAugmented = errors.New("container is not augmented")
+
+	// ErrInvalidInterval means that an interval doesn't contain any point.
+	ErrInvalidInterval = errors.New("interval is empty")
+
+	// ErrDimensionMismatch means that a point doesn't have as many coordinates as the space it's
+	// used in has dimensions.
+	ErrDimensionMismatch = errors.New("point has wrong number of dimensions")
+
+	// ErrOverlap means that containers can't be concatenated because some data of the first
+	// one isn't ordered before all data of the second one.
+	ErrOverlap = errors.New("containers overlap")
+
+	// ErrCycle means that a graph has a cycle where it must be acyclic.
+	ErrCycle = errors.New("graph has a cycle")
+
+	// ErrNegativeWeight means that a graph has an edge of negative weight where all weights must
+	// be nonnegative.
+	ErrNegativeWeight = errors.New("edge has negative weight")
+
+	// ErrNoPath means that no path leads from one vertex of a graph to another.
+	ErrNoPath = errors.New("no path between vertices")
+
+	// ErrTooLarge means that data costs more than the whole capacity of a container.
+	ErrTooLarge = errors.New("data exceeds capacity")
+
+	// ErrCorrupted is returned by Validate when the internal structure of a container
+	// violates one of its invariants.
+	ErrCorrupted = errors.New("container is corrupted")
+
+	// ErrKeyChanged is returned by Update when Setter.Set changed the key of the data, which
+	// should be done with ReKey instead.
+	ErrKeyChanged = errors.New("key of data was changed")
+
+	// ErrInvalidTraversal means that a sequence is not the traversal it was claimed to be.
+	ErrInvalidTraversal = errors.New("sequence is not a valid traversal")
+
+	// ErrNotSupported means that a container can't perform an operation it was asked for,
+	// such as a traversal order that doesn't apply to its structure.
+	ErrNotSupported = errors.New("operation not supported")
+
+	// ErrNotInterface is returned by Checker.Observe for a key that doesn't implement Interface,
+	// which can't be checked.
+	ErrNotInterface = errors.New("key doesn't implement Interface")
+
+	// ErrConcurrentModification means that a container was structurally modified while it was
+	// being traversed, so the traversal was stopped.
+	ErrConcurrentModification = errors.New("container was modified during traversal")
+
+	// ErrCallbackPanic means that a user-defined method or function panicked in a goroutine
+	// started by the library.
+	ErrCallbackPanic = errors.New("callback panicked")
 )
+
+// CallbackPanic records a panic recovered from a user callback executed in a goroutine started
+// by the library, so that it can be handed back to the calling goroutine. It wraps ErrCallbackPanic.
+type CallbackPanic struct {
+	Value interface{} // the value passed to panic
+	Stack []byte      // the stack of the panicking goroutine
+}
+
+// Error implements the error interface.
+func (cp *CallbackPanic) Error() string {
+	return fmt.Sprintf("%v: %v\n%s", ErrCallbackPanic, cp.Value, cp.Stack)
+}
+
+// Unwrap returns ErrCallbackPanic.
+func (cp *CallbackPanic) Unwrap() error {
+	return ErrCallbackPanic
+}
@@ -0,0 +1,115 @@
+// Package disjointset implements disjoint-set forests, also known as union-find, which keep a
+// partition of elements into sets under merging and find the set of any element in nearly
+// constant amortized time.
+package disjointset
+
+import "fmt"
+
+// DisjointSet represents a partition of the dense elements 0 to Len()-1. Every set is a tree
+// whose root stands for it, the smaller tree being linked under the root of the larger one on
+// union and paths being compressed on Find. DisjointSet is not safe for concurrent use, as Find
+// modifies it.
+type DisjointSet struct {
+	parent []int
+	size   []int // the size of the set of each root
+	count  int
+}
+
+// NewDisjointSet returns a partition of the elements 0 to n-1, each in a set of its own.
+func NewDisjointSet(n int) *DisjointSet {
+	ds := &DisjointSet{}
+	for i := 0; i < n; i++ {
+		ds.MakeSet()
+	}
+	return ds
+}
+
+// MakeSet adds a new element in a set of its own and returns it, which is the former Len.
+func (ds *DisjointSet) MakeSet() int {
+	x := len(ds.parent)
+	ds.parent = append(ds.parent, x)
+	ds.size = append(ds.size, 1)
+	ds.count++
+	return x
+}
+
+func (ds *DisjointSet) check(x int) {
+	if x < 0 || x >= len(ds.parent) {
+		panic(fmt.Sprintf("disjointset: element %d out of range [0, %d)", x, len(ds.parent)))
+	}
+}
+
+// Find returns the root of the set of x, which is the same for all elements of the set until
+// it's merged with another one. It panics if x is out of range.
+func (ds *DisjointSet) Find(x int) int {
+	ds.check(x)
+
+	root := x
+	for ds.parent[root] != root {
+		root = ds.parent[root]
+	}
+	for ds.parent[x] != root {
+		x, ds.parent[x] = ds.parent[x], root
+	}
+	return root
+}
+
+// Union merges the sets of x and y, and reports whether they were different sets. It panics
+// if either element is out of range.
+func (ds *DisjointSet) Union(x, y int) bool {
+	x, y = ds.Find(x), ds.Find(y)
+	if x == y {
+		return false
+	}
+
+	if ds.size[x] < ds.size[y] {
+		x, y = y, x
+	}
+	ds.parent[y] = x
+	ds.size[x] += ds.size[y]
+	ds.count--
+	return true
+}
+
+// Connected reports whether x and y are in the same set. It panics if either element is out
+// of range.
+func (ds *DisjointSet) Connected(x, y int) bool {
+	return ds.Find(x) == ds.Find(y)
+}
+
+// SetSize returns the number of elements in the set of x. It panics if x is out of range.
+func (ds *DisjointSet) SetSize(x int) int {
+	return ds.size[ds.Find(x)]
+}
+
+// Len returns the number of elements.
+func (ds *DisjointSet) Len() int {
+	return len(ds.parent)
+}
+
+// Count returns the number of sets.
+func (ds *DisjointSet) Count() int {
+	return ds.count
+}
+
+// Sets returns all sets, each in ascending order, ordered by their least elements.
+func (ds *DisjointSet) Sets() [][]int {
+	return groups(len(ds.parent), ds.count, ds.Find)
+}
+
+// groups groups the elements 0 to n-1 into count sets by their roots.
+func groups(n, count int, find func(int) int) [][]int {
+	sets := make([][]int, 0, count)
+	index := make(map[int]int, count)
+	for x := 0; x < n; x++ {
+		root := find(x)
+		i, ok := index[root]
+		if !ok {
+			i = len(sets)
+			index[root] = i
+			sets = append(sets, nil)
+		}
+		sets[i] = append(sets[i], x)
+	}
+	return sets
+}
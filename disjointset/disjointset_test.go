@@ -0,0 +1,183 @@
+package disjointset_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/disjointset"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// model is a naive partition labelling every element with its set.
+type model []int
+
+func newModel(n int) model {
+	m := make(model, n)
+	for i := range m {
+		m[i] = i
+	}
+	return m
+}
+
+func (m model) union(x, y int) bool {
+	lx, ly := m[x], m[y]
+	if lx == ly {
+		return false
+	}
+	for i := range m {
+		if m[i] == ly {
+			m[i] = lx
+		}
+	}
+	return true
+}
+
+func (m model) size(x int) int {
+	var n int
+	for _, l := range m {
+		if l == m[x] {
+			n++
+		}
+	}
+	return n
+}
+
+func (m model) count() int {
+	labels := make(map[int]bool)
+	for _, l := range m {
+		labels[l] = true
+	}
+	return len(labels)
+}
+
+// sets returns the sets of m the same way as Sets does.
+func (m model) sets() [][]int {
+	var sets [][]int
+	index := make(map[int]int)
+	for x, l := range m {
+		i, ok := index[l]
+		if !ok {
+			i = len(sets)
+			index[l] = i
+			sets = append(sets, nil)
+		}
+		sets[i] = append(sets[i], x)
+	}
+	return sets
+}
+
+func compareSets(s1, s2 [][]int) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if len(s1[i]) != len(s2[i]) {
+			return false
+		}
+		for j := range s1[i] {
+			if s1[i][j] != s2[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestDisjointSetRandom(t *testing.T) {
+	const n = 200
+	ds := disjointset.NewDisjointSet(n)
+	m := newModel(n)
+	if ds.Len() != n || ds.Count() != n {
+		t.Errorf("(%v != %v) or (%v != %v)", ds.Len(), n, ds.Count(), n)
+	}
+
+	for i := 0; i < 300; i++ {
+		x, y := r.Intn(n), r.Intn(n)
+		if got, want := ds.Union(x, y), m.union(x, y); got != want {
+			t.Fatalf("Union(%v, %v): %v != %v", x, y, got, want)
+		}
+
+		x, y = r.Intn(n), r.Intn(n)
+		if got, want := ds.Connected(x, y), m[x] == m[y]; got != want {
+			t.Fatalf("Connected(%v, %v): %v != %v", x, y, got, want)
+		}
+		if got, want := ds.SetSize(x), m.size(x); got != want {
+			t.Fatalf("SetSize(%v): %v != %v", x, got, want)
+		}
+		if ds.Count() != m.count() {
+			t.Fatalf("%v != %v", ds.Count(), m.count())
+		}
+	}
+
+	if !compareSets(ds.Sets(), m.sets()) {
+		t.Errorf("%v != %v", ds.Sets(), m.sets())
+	}
+	if x := ds.MakeSet(); x != n || ds.SetSize(x) != 1 || ds.Find(x) != x {
+		t.Errorf("%v != %v", x, n)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Find(%v) didn't panic", n+1)
+		}
+	}()
+	ds.Find(n + 1)
+}
+
+func TestDisjointSetMap(t *testing.T) {
+	m := disjointset.NewMap()
+	keys := []interface{}{"a", "b", "c", 1, 2.5, [2]int{3, 4}}
+	for _, k := range keys {
+		if err := m.MakeSet(k); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+	if err := m.MakeSet("a"); err != container.ErrDataExists {
+		t.Errorf("%v != %v", err, container.ErrDataExists)
+	}
+
+	for _, pair := range [][2]interface{}{{"a", 1}, {[2]int{3, 4}, "c"}, {1, "c"}} {
+		if ok, err := m.Union(pair[0], pair[1]); !ok || err != nil {
+			t.Errorf("(%v != true) or (%v != nil)", ok, err)
+		}
+	}
+	if ok, err := m.Union("c", "a"); ok || err != nil {
+		t.Errorf("(%v != false) or (%v != nil)", ok, err)
+	}
+	if _, err := m.Union("a", "z"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+
+	if ok, _ := m.Connected("a", [2]int{3, 4}); !ok {
+		t.Errorf("a and [3 4] are not connected")
+	}
+	if ok, _ := m.Connected("b", 2.5); ok {
+		t.Errorf("b and 2.5 are connected")
+	}
+	if n, err := m.SetSize("c"); n != 4 || err != nil {
+		t.Errorf("(%v != 4) or (%v != nil)", n, err)
+	}
+	if _, err := m.SetSize("z"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+
+	r1, _ := m.Find(1)
+	r2, err := m.Find("c")
+	if r1 != r2 || err != nil {
+		t.Errorf("(%v != %v) or (%v != nil)", r1, r2, err)
+	}
+	if _, err := m.Find("z"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+
+	sets := m.Sets()
+	if m.Len() != 6 || m.Count() != 3 || len(sets) != 3 {
+		t.Fatalf("(%v != 6) or (%v != 3) or (%v != 3)", m.Len(), m.Count(), len(sets))
+	}
+	if len(sets[0]) != 4 || sets[0][0] != "a" || sets[0][3] != [2]int{3, 4} || sets[1][0] != "b" || sets[2][0] != 2.5 {
+		t.Errorf("%v", sets)
+	}
+}
@@ -0,0 +1,102 @@
+package disjointset
+
+import "github.com/NzKSO/container"
+
+// Map represents a partition of arbitrary keys, which must be comparable as map keys are. It
+// numbers the keys in order of MakeSet and keeps them in a DisjointSet. Map is not safe for
+// concurrent use.
+type Map struct {
+	ds    DisjointSet
+	index map[interface{}]int
+	keys  []interface{}
+}
+
+// NewMap returns an empty partition.
+func NewMap() *Map {
+	return &Map{index: make(map[interface{}]int)}
+}
+
+// MakeSet adds key in a set of its own. If key already exists, returns ErrDataExists.
+func (m *Map) MakeSet(key interface{}) error {
+	if _, ok := m.index[key]; ok {
+		return container.ErrDataExists
+	}
+	m.index[key] = m.ds.MakeSet()
+	m.keys = append(m.keys, key)
+	return nil
+}
+
+// Find returns the key standing for the set of key, which is the same for all keys of the set
+// until it's merged with another one. If key doesn't exist, returns ErrNotExist.
+func (m *Map) Find(key interface{}) (interface{}, error) {
+	x, ok := m.index[key]
+	if !ok {
+		return nil, container.ErrNotExist
+	}
+	return m.keys[m.ds.Find(x)], nil
+}
+
+// indices returns the elements of both keys.
+func (m *Map) indices(k1, k2 interface{}) (int, int, error) {
+	x, ok := m.index[k1]
+	if !ok {
+		return 0, 0, container.ErrNotExist
+	}
+	y, ok := m.index[k2]
+	if !ok {
+		return 0, 0, container.ErrNotExist
+	}
+	return x, y, nil
+}
+
+// Union merges the sets of k1 and k2, and reports whether they were different sets. If either
+// key doesn't exist, returns ErrNotExist.
+func (m *Map) Union(k1, k2 interface{}) (bool, error) {
+	x, y, err := m.indices(k1, k2)
+	if err != nil {
+		return false, err
+	}
+	return m.ds.Union(x, y), nil
+}
+
+// Connected reports whether k1 and k2 are in the same set. If either key doesn't exist, returns
+// ErrNotExist.
+func (m *Map) Connected(k1, k2 interface{}) (bool, error) {
+	x, y, err := m.indices(k1, k2)
+	if err != nil {
+		return false, err
+	}
+	return m.ds.Connected(x, y), nil
+}
+
+// SetSize returns the number of keys in the set of key. If key doesn't exist, returns ErrNotExist.
+func (m *Map) SetSize(key interface{}) (int, error) {
+	x, ok := m.index[key]
+	if !ok {
+		return 0, container.ErrNotExist
+	}
+	return m.ds.SetSize(x), nil
+}
+
+// Len returns the number of keys.
+func (m *Map) Len() int {
+	return len(m.keys)
+}
+
+// Count returns the number of sets.
+func (m *Map) Count() int {
+	return m.ds.Count()
+}
+
+// Sets returns all sets, with the keys of each and the sets themselves in order of MakeSet.
+func (m *Map) Sets() [][]interface{} {
+	sets := make([][]interface{}, 0, m.ds.Count())
+	for _, set := range m.ds.Sets() {
+		keys := make([]interface{}, len(set))
+		for i, x := range set {
+			keys[i] = m.keys[x]
+		}
+		sets = append(sets, keys)
+	}
+	return sets
+}
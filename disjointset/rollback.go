@@ -0,0 +1,133 @@
+package disjointset
+
+import "fmt"
+
+// change records a MakeSet, with parent -1, or a union linking the root child under parent.
+type change struct {
+	child, parent int
+}
+
+// RollbackSet represents a partition of the dense elements 0 to Len()-1 whose changes can be
+// undone in reverse order, which offline algorithms such as dynamic connectivity over a segment
+// tree of time rely on. It links by size like DisjointSet but never compresses paths, so that
+// every change touches O(1) entries, and Find takes O(log n) time. Unlike DisjointSet, Find doesn't
+// modify it.
+type RollbackSet struct {
+	parent  []int
+	size    []int
+	count   int
+	history []change
+}
+
+// NewRollbackSet returns a partition of the elements 0 to n-1, each in a set of its own, with
+// an empty history.
+func NewRollbackSet(n int) *RollbackSet {
+	rs := &RollbackSet{}
+	for i := 0; i < n; i++ {
+		rs.MakeSet()
+	}
+	rs.history = nil
+	return rs
+}
+
+// MakeSet adds a new element in a set of its own and returns it, which is the former Len.
+func (rs *RollbackSet) MakeSet() int {
+	x := len(rs.parent)
+	rs.parent = append(rs.parent, x)
+	rs.size = append(rs.size, 1)
+	rs.count++
+	rs.history = append(rs.history, change{x, -1})
+	return x
+}
+
+// Find returns the root of the set of x. It panics if x is out of range.
+func (rs *RollbackSet) Find(x int) int {
+	if x < 0 || x >= len(rs.parent) {
+		panic(fmt.Sprintf("disjointset: element %d out of range [0, %d)", x, len(rs.parent)))
+	}
+
+	for rs.parent[x] != x {
+		x = rs.parent[x]
+	}
+	return x
+}
+
+// Union merges the sets of x and y, and reports whether they were different sets. Only a merge
+// is recorded in the history. It panics if either element is out of range.
+func (rs *RollbackSet) Union(x, y int) bool {
+	x, y = rs.Find(x), rs.Find(y)
+	if x == y {
+		return false
+	}
+
+	if rs.size[x] < rs.size[y] {
+		x, y = y, x
+	}
+	rs.parent[y] = x
+	rs.size[x] += rs.size[y]
+	rs.count--
+	rs.history = append(rs.history, change{y, x})
+	return true
+}
+
+// Connected reports whether x and y are in the same set. It panics if either element is out
+// of range.
+func (rs *RollbackSet) Connected(x, y int) bool {
+	return rs.Find(x) == rs.Find(y)
+}
+
+// SetSize returns the number of elements in the set of x. It panics if x is out of range.
+func (rs *RollbackSet) SetSize(x int) int {
+	return rs.size[rs.Find(x)]
+}
+
+// Len returns the number of elements.
+func (rs *RollbackSet) Len() int {
+	return len(rs.parent)
+}
+
+// Count returns the number of sets.
+func (rs *RollbackSet) Count() int {
+	return rs.count
+}
+
+// Sets returns all sets, each in ascending order, ordered by their least elements.
+func (rs *RollbackSet) Sets() [][]int {
+	return groups(len(rs.parent), rs.count, rs.Find)
+}
+
+// Snapshot returns the number of changes in the history, to be passed to Rollback later.
+func (rs *RollbackSet) Snapshot() int {
+	return len(rs.history)
+}
+
+// Undo undoes the last change, either a merge or a MakeSet, and reports whether there was one.
+func (rs *RollbackSet) Undo() bool {
+	if len(rs.history) == 0 {
+		return false
+	}
+
+	c := rs.history[len(rs.history)-1]
+	rs.history = rs.history[:len(rs.history)-1]
+	if c.parent == -1 {
+		rs.parent = rs.parent[:c.child]
+		rs.size = rs.size[:c.child]
+		rs.count--
+	} else {
+		rs.parent[c.child] = c.child
+		rs.size[c.parent] -= rs.size[c.child]
+		rs.count++
+	}
+	return true
+}
+
+// Rollback undoes all changes made since Snapshot returned snapshot. It panics if snapshot is
+// greater than the current Snapshot.
+func (rs *RollbackSet) Rollback(snapshot int) {
+	if snapshot < 0 || snapshot > len(rs.history) {
+		panic(fmt.Sprintf("disjointset: snapshot %d out of range [0, %d]", snapshot, len(rs.history)))
+	}
+	for len(rs.history) > snapshot {
+		rs.Undo()
+	}
+}
@@ -0,0 +1,67 @@
+package disjointset_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container/disjointset"
+)
+
+func TestRollbackSet(t *testing.T) {
+	const n = 100
+	rs := disjointset.NewRollbackSet(n)
+	if rs.Snapshot() != 0 || rs.Undo() {
+		t.Errorf("%v != 0", rs.Snapshot())
+	}
+
+	// Every snapshot is checked against the model it was taken with after rolling back to it.
+	type saved struct {
+		snapshot int
+		m        model
+	}
+	m := newModel(n)
+	var snaps []saved
+	for i := 0; i < 200; i++ {
+		if i%20 == 0 {
+			snaps = append(snaps, saved{rs.Snapshot(), append(model(nil), m...)})
+		}
+		x, y := r.Intn(n), r.Intn(n)
+		if got, want := rs.Union(x, y), m.union(x, y); got != want {
+			t.Fatalf("Union(%v, %v): %v != %v", x, y, got, want)
+		}
+	}
+	if !compareSets(rs.Sets(), m.sets()) || rs.Count() != m.count() {
+		t.Fatalf("%v != %v", rs.Sets(), m.sets())
+	}
+
+	for i := len(snaps) - 1; i >= 0; i-- {
+		rs.Rollback(snaps[i].snapshot)
+		m = snaps[i].m
+		if !compareSets(rs.Sets(), m.sets()) || rs.Count() != m.count() {
+			t.Fatalf("%v != %v", rs.Sets(), m.sets())
+		}
+		for x := 0; x < n; x++ {
+			if rs.SetSize(x) != m.size(x) {
+				t.Fatalf("SetSize(%v): %v != %v", x, rs.SetSize(x), m.size(x))
+			}
+		}
+	}
+
+	// MakeSet is undone as well.
+	snapshot := rs.Snapshot()
+	x := rs.MakeSet()
+	rs.Union(x, 0)
+	if !rs.Connected(x, 0) || rs.Len() != n+1 {
+		t.Errorf("%v != %v", rs.Len(), n+1)
+	}
+	rs.Rollback(snapshot)
+	if rs.Len() != n || rs.Count() != n || rs.SetSize(0) != 1 {
+		t.Errorf("(%v != %v) or (%v != %v)", rs.Len(), n, rs.Count(), n)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Rollback(%v) didn't panic", snapshot+1)
+		}
+	}()
+	rs.Rollback(snapshot + 1)
+}
@@ -0,0 +1,117 @@
+// Package fenwick implements a Fenwick tree, also known as a binary indexed tree, which keeps
+// the prefix sums of an array of integers under point updates in O(log n) time and O(n) space.
+package fenwick
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Fenwick represents an array of n int64 values indexed from 0 to n-1, all of which are 0
+// initially. The i-th entry of the underlying array, indexed from 1, holds the sum of the
+// values in (i - lowbit(i), i]. Fenwick is not safe for concurrent use.
+type Fenwick struct {
+	tree []int64 // tree[0] is unused
+}
+
+// NewFenwick returns a Fenwick tree of n values, all of which are 0.
+func NewFenwick(n int) *Fenwick {
+	if n < 0 {
+		n = 0
+	}
+	return &Fenwick{make([]int64, n+1)}
+}
+
+// FromSlice returns a Fenwick tree of a copy of values, which is built in O(n) time.
+func FromSlice(values []int64) *Fenwick {
+	f := NewFenwick(len(values))
+	copy(f.tree[1:], values)
+	for i := 1; i < len(f.tree); i++ {
+		if j := i + i&-i; j < len(f.tree) {
+			f.tree[j] += f.tree[i]
+		}
+	}
+	return f
+}
+
+// Len returns the number of values.
+func (f *Fenwick) Len() int {
+	return len(f.tree) - 1
+}
+
+func (f *Fenwick) check(i, n int) {
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("fenwick: index %d out of range [0, %d)", i, n))
+	}
+}
+
+// Add adds delta to the value at i. It panics if i is out of range.
+func (f *Fenwick) Add(i int, delta int64) {
+	f.check(i, f.Len())
+	for i++; i < len(f.tree); i += i & -i {
+		f.tree[i] += delta
+	}
+}
+
+// PrefixSum returns the sum of the first n values, that is the values in [0, n). It panics if
+// n is greater than Len.
+func (f *Fenwick) PrefixSum(n int) int64 {
+	f.check(n, len(f.tree))
+
+	var sum int64
+	for ; n > 0; n -= n & -n {
+		sum += f.tree[n]
+	}
+	return sum
+}
+
+// RangeSum returns the sum of the values in [lo, hi), which is 0 if lo >= hi. It panics if
+// either bound is out of range.
+func (f *Fenwick) RangeSum(lo, hi int) int64 {
+	if lo >= hi {
+		f.check(lo, len(f.tree))
+		return 0
+	}
+	return f.PrefixSum(hi) - f.PrefixSum(lo)
+}
+
+// Get returns the value at i. It panics if i is out of range.
+func (f *Fenwick) Get(i int) int64 {
+	f.check(i, f.Len())
+	return f.RangeSum(i, i+1)
+}
+
+// Set sets the value at i to v. It panics if i is out of range.
+func (f *Fenwick) Set(i int, v int64) {
+	f.Add(i, v-f.Get(i))
+}
+
+// LowerBound returns the least n such that PrefixSum(n+1) >= sum, or Len if there is no such
+// n, in O(log n) time. The result is meaningful only if no value is negative, which makes the
+// prefix sums nondecreasing.
+func (f *Fenwick) LowerBound(sum int64) int {
+	n := f.Len()
+	if n == 0 {
+		return 0
+	}
+
+	var pos int
+	for step := 1 << (bits.Len(uint(n)) - 1); step > 0; step >>= 1 {
+		if next := pos + step; next <= n && f.tree[next] < sum {
+			pos = next
+			sum -= f.tree[next]
+		}
+	}
+	return pos
+}
+
+// Values returns a copy of all values in order, in O(n) time.
+func (f *Fenwick) Values() []int64 {
+	values := append([]int64(nil), f.tree...)
+	for i := len(values) - 1; i > 0; i-- {
+		if j := i + i&-i; j < len(values) {
+			values[j] -= values[i]
+		}
+	}
+	return values[1:]
+}
@@ -0,0 +1,110 @@
+package fenwick_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container/fenwick"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func sum(values []int64) int64 {
+	var s int64
+	for _, v := range values {
+		s += v
+	}
+	return s
+}
+
+func TestFenwickRandom(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 64, 100} {
+		model := make([]int64, n)
+		for i := range model {
+			model[i] = r.Int63n(100)
+		}
+		f := fenwick.FromSlice(model)
+		if f.Len() != n {
+			t.Errorf("%v != %v", f.Len(), n)
+		}
+
+		for k := 0; k < 500 && n > 0; k++ {
+			i := r.Intn(n)
+			switch r.Intn(3) {
+			case 0:
+				d := r.Int63n(100) - 50
+				f.Add(i, d)
+				model[i] += d
+			case 1:
+				v := r.Int63n(100)
+				f.Set(i, v)
+				model[i] = v
+			default:
+				if got := f.Get(i); got != model[i] {
+					t.Fatalf("%v != %v", got, model[i])
+				}
+			}
+
+			lo, hi := r.Intn(n+1), r.Intn(n+1)
+			if got, want := f.PrefixSum(hi), sum(model[:hi]); got != want {
+				t.Fatalf("PrefixSum(%v): %v != %v", hi, got, want)
+			}
+			if lo <= hi {
+				if got, want := f.RangeSum(lo, hi), sum(model[lo:hi]); got != want {
+					t.Fatalf("RangeSum(%v, %v): %v != %v", lo, hi, got, want)
+				}
+			} else if got := f.RangeSum(lo, hi); got != 0 {
+				t.Fatalf("%v != 0", got)
+			}
+		}
+
+		got := f.Values()
+		for i := range model {
+			if got[i] != model[i] {
+				t.Errorf("%v != %v", got, model)
+				break
+			}
+		}
+	}
+}
+
+func TestFenwickLowerBound(t *testing.T) {
+	values := []int64{3, 0, 2, 5, 0, 0, 1}
+	f := fenwick.FromSlice(values)
+
+	for s := int64(-1); s <= 12; s++ {
+		want := 0
+		for want < len(values) && sum(values[:want+1]) < s {
+			want++
+		}
+		if got := f.LowerBound(s); got != want {
+			t.Errorf("LowerBound(%v): %v != %v", s, got, want)
+		}
+	}
+	if got := fenwick.NewFenwick(0).LowerBound(1); got != 0 {
+		t.Errorf("%v != 0", got)
+	}
+}
+
+func TestFenwickOutOfRange(t *testing.T) {
+	f := fenwick.NewFenwick(4)
+	for name, fn := range map[string]func(){
+		"Add":       func() { f.Add(4, 1) },
+		"Get":       func() { f.Get(-1) },
+		"PrefixSum": func() { f.PrefixSum(5) },
+		"RangeSum":  func() { f.RangeSum(0, 5) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v didn't panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+	if f.PrefixSum(4) != 0 || f.RangeSum(4, 4) != 0 {
+		t.Errorf("sums of zeros are not 0")
+	}
+}
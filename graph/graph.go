@@ -0,0 +1,250 @@
+// Package graph implements directed and undirected weighted graphs stored as adjacency lists,
+// with traversals built on the stacks and queues of this library and the usual algorithms on them.
+package graph
+
+import "github.com/NzKSO/container"
+
+// Edge is an edge of a graph, which leads from From to To in a directed graph and joins them in
+// an undirected one.
+type Edge struct {
+	From, To interface{}
+	Weight   float64
+}
+
+// arc is an edge as seen from the vertex it leaves.
+type arc struct {
+	to     *vertex
+	weight float64
+}
+
+type vertex struct {
+	key  interface{}
+	id   int // the position of the vertex in Graph.vertices
+	arcs []arc
+}
+
+// Graph represents a graph whose vertices are arbitrary keys, which must be comparable as map
+// keys are. Every vertex keeps the arcs leaving it in order of insertion, an undirected edge
+// being kept as an arc at either end, and vertices are kept in order of insertion too, which
+// makes all traversals and algorithms deterministic. Parallel edges are not allowed, but loops
+// are. Every structural modification of the graph is counted, so that iterators can detect it.
+// Graph is not safe for concurrent use.
+type Graph struct {
+	directed bool
+	vertices []*vertex
+	index    map[interface{}]*vertex
+	edges    int
+	mods     uint64
+}
+
+// NewDirected returns an empty directed graph.
+func NewDirected() *Graph {
+	return &Graph{directed: true, index: make(map[interface{}]*vertex)}
+}
+
+// NewUndirected returns an empty undirected graph.
+func NewUndirected() *Graph {
+	return &Graph{index: make(map[interface{}]*vertex)}
+}
+
+// Directed reports whether the graph is directed.
+func (g *Graph) Directed() bool {
+	return g.directed
+}
+
+// Order returns the number of vertices.
+func (g *Graph) Order() int {
+	return len(g.vertices)
+}
+
+// Size returns the number of edges, each undirected edge counted once.
+func (g *Graph) Size() int {
+	return g.edges
+}
+
+// Empty returns true if the graph has no vertices, otherwise false.
+func (g *Graph) Empty() bool {
+	return len(g.vertices) == 0
+}
+
+// Reset drops all vertices and edges of the graph and back to its initial state.
+func (g *Graph) Reset() {
+	g.vertices = nil
+	g.index = make(map[interface{}]*vertex)
+	g.edges = 0
+	g.mods++
+}
+
+func (g *Graph) addVertex(key interface{}) *vertex {
+	v := &vertex{key: key, id: len(g.vertices)}
+	g.vertices = append(g.vertices, v)
+	g.index[key] = v
+	g.mods++
+	return v
+}
+
+// AddVertex adds vertex v to the graph. If v already exists, returns ErrDataExists.
+func (g *Graph) AddVertex(v interface{}) error {
+	if _, ok := g.index[v]; ok {
+		return container.ErrDataExists
+	}
+	g.addVertex(v)
+	return nil
+}
+
+// HasVertex reports whether v is a vertex of the graph.
+func (g *Graph) HasVertex(v interface{}) bool {
+	_, ok := g.index[v]
+	return ok
+}
+
+// Vertices returns all vertices in order of insertion.
+func (g *Graph) Vertices() []interface{} {
+	ret := make([]interface{}, len(g.vertices))
+	for i, v := range g.vertices {
+		ret[i] = v.key
+	}
+	return ret
+}
+
+// arcTo returns the index of the arc from u to v, or -1 if there is none.
+func (u *vertex) arcTo(v *vertex) int {
+	for i, a := range u.arcs {
+		if a.to == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeArc removes the i-th arc of u keeping the order of the others.
+func (u *vertex) removeArc(i int) {
+	u.arcs = append(u.arcs[:i], u.arcs[i+1:]...)
+}
+
+// AddEdge adds an edge of weight from from to to, adding either vertex first if it doesn't exist.
+// If the edge already exists, which in an undirected graph is also the case if it exists from to
+// to from, returns ErrDataExists.
+func (g *Graph) AddEdge(from, to interface{}, weight float64) error {
+	u, v := g.index[from], g.index[to]
+	if u != nil && v != nil && u.arcTo(v) != -1 {
+		return container.ErrDataExists
+	}
+
+	if u == nil {
+		u = g.addVertex(from)
+	}
+	if v == nil {
+		v = g.addVertex(to)
+	}
+	u.arcs = append(u.arcs, arc{v, weight})
+	if !g.directed && u != v {
+		v.arcs = append(v.arcs, arc{u, weight})
+	}
+	g.edges++
+	g.mods++
+	return nil
+}
+
+// edge returns both ends of the edge from from to to, or ErrNotExist if there is no such edge.
+func (g *Graph) edge(from, to interface{}) (*vertex, *vertex, int, error) {
+	u, v := g.index[from], g.index[to]
+	if u == nil || v == nil {
+		return nil, nil, -1, container.ErrNotExist
+	}
+	i := u.arcTo(v)
+	if i == -1 {
+		return nil, nil, -1, container.ErrNotExist
+	}
+	return u, v, i, nil
+}
+
+// HasEdge reports whether there is an edge from from to to.
+func (g *Graph) HasEdge(from, to interface{}) bool {
+	_, _, _, err := g.edge(from, to)
+	return err == nil
+}
+
+// Weight returns the weight of the edge from from to to. If there is no such edge, returns ErrNotExist.
+func (g *Graph) Weight(from, to interface{}) (float64, error) {
+	u, _, i, err := g.edge(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return u.arcs[i].weight, nil
+}
+
+// RemoveEdge removes the edge from from to to. If there is no such edge, returns ErrNotExist.
+func (g *Graph) RemoveEdge(from, to interface{}) error {
+	u, v, i, err := g.edge(from, to)
+	if err != nil {
+		return err
+	}
+
+	u.removeArc(i)
+	if !g.directed && u != v {
+		v.removeArc(v.arcTo(u))
+	}
+	g.edges--
+	g.mods++
+	return nil
+}
+
+// RemoveVertex removes vertex v and all edges incident to it in O(V + E) time. If v doesn't
+// exist, returns ErrNotExist.
+func (g *Graph) RemoveVertex(v interface{}) error {
+	x := g.index[v]
+	if x == nil {
+		return container.ErrNotExist
+	}
+
+	// An undirected edge to another vertex is counted once by the arcs of x, after any loop
+	// of x is removed along with the arcs to it.
+	for _, u := range g.vertices {
+		if i := u.arcTo(x); i != -1 {
+			u.removeArc(i)
+			if g.directed || u == x {
+				g.edges--
+			}
+		}
+	}
+	g.edges -= len(x.arcs)
+
+	g.vertices = append(g.vertices[:x.id], g.vertices[x.id+1:]...)
+	for i := x.id; i < len(g.vertices); i++ {
+		g.vertices[i].id = i
+	}
+	delete(g.index, v)
+	g.mods++
+	return nil
+}
+
+// Neighbors returns the vertices that the edges leaving v lead to in order of insertion of the
+// edges, which for an undirected graph are all vertices adjacent to v. If v doesn't exist,
+// returns ErrNotExist.
+func (g *Graph) Neighbors(v interface{}) ([]interface{}, error) {
+	x := g.index[v]
+	if x == nil {
+		return nil, container.ErrNotExist
+	}
+
+	ret := make([]interface{}, len(x.arcs))
+	for i, a := range x.arcs {
+		ret[i] = a.to.key
+	}
+	return ret, nil
+}
+
+// Edges returns all edges, ordered by the vertex they leave and then by insertion. An undirected
+// edge is returned once, from whichever end comes first in order of insertion of the vertices.
+func (g *Graph) Edges() []Edge {
+	ret := make([]Edge, 0, g.edges)
+	for _, u := range g.vertices {
+		for _, a := range u.arcs {
+			if g.directed || u.id <= a.to.id {
+				ret = append(ret, Edge{u.key, a.to.key, a.weight})
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,137 @@
+package graph_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/graph"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func compareSlice(s1, s2 []interface{}) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if s1[i] != s2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// randomGraph returns a graph of n vertices 0 to n-1 with about m random edges of weights in
+// [0, 10), which may include loops.
+func randomGraph(directed bool, n, m int) *graph.Graph {
+	g := graph.NewUndirected()
+	if directed {
+		g = graph.NewDirected()
+	}
+	for v := 0; v < n; v++ {
+		g.AddVertex(v)
+	}
+	for i := 0; i < m; i++ {
+		g.AddEdge(r.Intn(n), r.Intn(n), float64(r.Intn(10)))
+	}
+	return g
+}
+
+func TestGraphEdges(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		g := graph.NewUndirected()
+		if directed {
+			g = graph.NewDirected()
+		}
+		if g.Directed() != directed || !g.Empty() {
+			t.Errorf("%v != %v", g.Directed(), directed)
+		}
+
+		for _, e := range []graph.Edge{{"a", "b", 1}, {"b", "c", 2}, {"a", "c", 3}, {"c", "c", 4}, {"d", "a", 5}} {
+			if err := g.AddEdge(e.From, e.To, e.Weight); err != nil {
+				t.Errorf("%v != nil", err)
+			}
+		}
+		if err := g.AddEdge("a", "b", 0); err != container.ErrDataExists {
+			t.Errorf("%v != %v", err, container.ErrDataExists)
+		}
+		if err := g.AddEdge("b", "a", 0); (err == nil) != directed {
+			t.Errorf("AddEdge(b, a): %v", err)
+		}
+		if err := g.AddVertex("e"); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+		if err := g.AddVertex("a"); err != container.ErrDataExists {
+			t.Errorf("%v != %v", err, container.ErrDataExists)
+		}
+
+		size := 5
+		if directed {
+			size = 6
+		}
+		if g.Order() != 5 || g.Size() != size || len(g.Edges()) != size {
+			t.Errorf("(%v != 5) or (%v != %v)", g.Order(), g.Size(), size)
+		}
+		if !compareSlice(g.Vertices(), []interface{}{"a", "b", "c", "d", "e"}) {
+			t.Errorf("%v", g.Vertices())
+		}
+		if w, err := g.Weight("c", "b"); (err == nil) == directed || !directed && w != 2 {
+			t.Errorf("Weight(c, b): %v, %v", w, err)
+		}
+
+		nb, _ := g.Neighbors("a")
+		want := []interface{}{"b", "c", "d"}
+		if directed {
+			want = []interface{}{"b", "c"}
+		}
+		if !compareSlice(nb, want) {
+			t.Errorf("%v != %v", nb, want)
+		}
+		if _, err := g.Neighbors("z"); err != container.ErrNotExist {
+			t.Errorf("%v != %v", err, container.ErrNotExist)
+		}
+
+		if err := g.RemoveEdge("c", "c"); err != nil || g.HasEdge("c", "c") {
+			t.Errorf("%v != nil", err)
+		}
+		if err := g.RemoveEdge("b", "d"); err != container.ErrNotExist {
+			t.Errorf("%v != %v", err, container.ErrNotExist)
+		}
+		if err := g.RemoveVertex("a"); err != nil || g.HasVertex("a") {
+			t.Errorf("%v != nil", err)
+		}
+		if g.Order() != 4 || g.Size() != 1 || len(g.Edges()) != 1 {
+			t.Errorf("(%v != 4) or (%v != 1): %v", g.Order(), g.Size(), g.Edges())
+		}
+		if err := g.RemoveVertex("a"); err != container.ErrNotExist {
+			t.Errorf("%v != %v", err, container.ErrNotExist)
+		}
+
+		g.Reset()
+		if !g.Empty() || g.Size() != 0 || g.HasVertex("b") {
+			t.Errorf("graph is not empty")
+		}
+	}
+}
+
+func TestGraphRemoveVertex(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		g := randomGraph(directed, 30, 120)
+		for _, v := range r.Perm(30) {
+			g.RemoveVertex(v)
+			if got := len(g.Edges()); got != g.Size() {
+				t.Fatalf("%v != %v", got, g.Size())
+			}
+			for _, e := range g.Edges() {
+				if e.From == v || e.To == v {
+					t.Fatalf("edge %v of removed vertex %v", e, v)
+				}
+			}
+		}
+		if !g.Empty() || g.Size() != 0 {
+			t.Errorf("(%v != 0) or (%v != 0)", g.Order(), g.Size())
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/queue"
+	"github.com/NzKSO/container/stack"
+)
+
+// visit is a vertex waiting in the frontier of an iterator, reached from parent.
+type visit struct {
+	v      *vertex
+	parent *vertex
+	depth  int
+}
+
+// Iterator walks the vertices reachable from a start vertex, breadth first or depth first, each
+// of them once. It's invalid after it walked all of them, or when the graph was structurally
+// modified since the iterator was created, in which case Err returns ErrConcurrentModification.
+type Iterator struct {
+	g       *Graph
+	mods    uint64
+	visited []bool
+	cur     visit
+	err     error
+
+	// The frontier, which is an LQueue for breadth first and an LStack for depth first search.
+	push  func(data ...interface{})
+	pop   func() interface{}
+	empty func() bool
+	bfs   bool
+}
+
+func (g *Graph) newIterator(start interface{}, bfs bool) (*Iterator, error) {
+	v := g.index[start]
+	if v == nil {
+		return nil, container.ErrNotExist
+	}
+
+	it := &Iterator{g: g, mods: g.mods, visited: make([]bool, len(g.vertices)), bfs: bfs}
+	if bfs {
+		lq := queue.NewLQueue()
+		it.push, it.pop, it.empty = lq.EnQueue, lq.LeQueue, lq.Empty
+		// Breadth first search marks vertices when they are queued, so that none is queued twice.
+		it.visited[v.id] = true
+	} else {
+		ls := stack.NewLStack()
+		it.push, it.pop, it.empty = ls.Push, ls.Pop, ls.Empty
+	}
+	it.push(visit{v: v})
+	return it, nil
+}
+
+// BFS returns an iterator walking the graph breadth first from start, which visits vertices in
+// order of their distance from start in edges. If start doesn't exist, returns ErrNotExist.
+func (g *Graph) BFS(start interface{}) (*Iterator, error) {
+	return g.newIterator(start, true)
+}
+
+// DFS returns an iterator walking the graph depth first from start, which visits vertices in
+// the same preorder as the recursive search following edges in order of insertion. If start
+// doesn't exist, returns ErrNotExist.
+func (g *Graph) DFS(start interface{}) (*Iterator, error) {
+	return g.newIterator(start, false)
+}
+
+// Next moves the iterator to the next vertex, it returns false if there is none or the graph
+// was modified.
+func (it *Iterator) Next() bool {
+	it.cur = visit{}
+	if it.err != nil {
+		return false
+	}
+	if it.g.mods != it.mods {
+		it.err = container.ErrConcurrentModification
+		return false
+	}
+
+	for !it.empty() {
+		vis := it.pop().(visit)
+		if it.bfs {
+			for _, a := range vis.v.arcs {
+				if !it.visited[a.to.id] {
+					it.visited[a.to.id] = true
+					it.push(visit{a.to, vis.v, vis.depth + 1})
+				}
+			}
+		} else {
+			// Depth first search marks vertices when they are popped, a vertex may be pushed by
+			// several others and is reached from the last of them. Arcs are pushed in reverse so
+			// that the first one is followed first.
+			if it.visited[vis.v.id] {
+				continue
+			}
+			it.visited[vis.v.id] = true
+			for i := len(vis.v.arcs) - 1; i >= 0; i-- {
+				if a := vis.v.arcs[i]; !it.visited[a.to.id] {
+					it.push(visit{a.to, vis.v, vis.depth + 1})
+				}
+			}
+		}
+
+		it.cur = vis
+		return true
+	}
+	return false
+}
+
+// Vertex returns the vertex the iterator is at, or nil if it's invalid.
+func (it *Iterator) Vertex() interface{} {
+	if it.cur.v == nil {
+		return nil
+	}
+	return it.cur.v.key
+}
+
+// Parent returns the vertex from which the iterator reached the current one, which is nil for
+// the start vertex and when the iterator is invalid.
+func (it *Iterator) Parent() interface{} {
+	if it.cur.parent == nil {
+		return nil
+	}
+	return it.cur.parent.key
+}
+
+// Depth returns the number of edges on the path the iterator took from the start vertex to the
+// current one, which is the distance between them for breadth first search, or -1 if it's invalid.
+func (it *Iterator) Depth() int {
+	if it.cur.v == nil {
+		return -1
+	}
+	return it.cur.depth
+}
+
+// Err returns ErrConcurrentModification if the graph was structurally modified while iterating,
+// otherwise nil.
+func (it *Iterator) Err() error {
+	return it.err
+}
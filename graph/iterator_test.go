@@ -0,0 +1,97 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/graph"
+)
+
+// collect returns the vertices, parents and depths visited by it.
+func collect(it *graph.Iterator) (vs, parents []interface{}, depths []int) {
+	for it.Next() {
+		vs = append(vs, it.Vertex())
+		parents = append(parents, it.Parent())
+		depths = append(depths, it.Depth())
+	}
+	return
+}
+
+// recursiveDFS returns the preorder of the recursive depth first search from v.
+func recursiveDFS(g *graph.Graph, v interface{}, visited map[interface{}]bool, order *[]interface{}) {
+	visited[v] = true
+	*order = append(*order, v)
+	nb, _ := g.Neighbors(v)
+	for _, w := range nb {
+		if !visited[w] {
+			recursiveDFS(g, w, visited, order)
+		}
+	}
+}
+
+func TestGraphBFS(t *testing.T) {
+	g := graph.NewDirected()
+	for _, e := range [][2]int{{0, 1}, {0, 2}, {1, 3}, {2, 3}, {3, 4}, {4, 0}, {5, 0}} {
+		g.AddEdge(e[0], e[1], 1)
+	}
+
+	it, err := g.BFS(0)
+	if err != nil {
+		t.Fatalf("%v != nil", err)
+	}
+	vs, parents, depths := collect(it)
+	if !compareSlice(vs, []interface{}{0, 1, 2, 3, 4}) {
+		t.Errorf("%v != [0 1 2 3 4]", vs)
+	}
+	if !compareSlice(parents, []interface{}{nil, 0, 0, 1, 3}) {
+		t.Errorf("%v != [<nil> 0 0 1 3]", parents)
+	}
+	for i, d := range []int{0, 1, 1, 2, 3} {
+		if depths[i] != d {
+			t.Errorf("%v != [0 1 1 2 3]", depths)
+			break
+		}
+	}
+	if it.Next() || it.Vertex() != nil || it.Depth() != -1 || it.Err() != nil {
+		t.Errorf("iterator is still valid")
+	}
+
+	if _, err := g.BFS(6); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+}
+
+func TestGraphDFS(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		g := randomGraph(directed, 50, 100)
+		for v := 0; v < 50; v += 7 {
+			var want []interface{}
+			recursiveDFS(g, v, make(map[interface{}]bool), &want)
+
+			it, _ := g.DFS(v)
+			vs, parents, depths := collect(it)
+			if !compareSlice(vs, want) {
+				t.Fatalf("%v != %v", vs, want)
+			}
+
+			// Every vertex is a neighbor of its parent, one level below it.
+			depth := map[interface{}]int{v: 0}
+			for i := 1; i < len(vs); i++ {
+				if !g.HasEdge(parents[i], vs[i]) || depths[i] != depth[parents[i]]+1 {
+					t.Fatalf("%v reached from %v at depth %v", vs[i], parents[i], depths[i])
+				}
+				depth[vs[i]] = depths[i]
+			}
+		}
+	}
+}
+
+func TestGraphIteratorModified(t *testing.T) {
+	g := randomGraph(false, 10, 30)
+	it, _ := g.DFS(0)
+	it.Next()
+	g.AddVertex(10)
+	if it.Next() || it.Err() != container.ErrConcurrentModification {
+		t.Errorf("%v != %v", it.Err(), container.ErrConcurrentModification)
+	}
+}
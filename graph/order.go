@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/disjointset"
+	"github.com/NzKSO/container/stack"
+)
+
+// CycleError is returned by TopologicalSort when the graph has a cycle. It wraps ErrCycle.
+type CycleError struct {
+	// Cycle holds the vertices of the cycle in order, an edge leading from each of them to the
+	// next one and from the last one back to the first.
+	Cycle []interface{}
+}
+
+// Error implements the error interface.
+func (ce *CycleError) Error() string {
+	return fmt.Sprintf("%v: %v", container.ErrCycle, ce.Cycle)
+}
+
+// Unwrap returns ErrCycle.
+func (ce *CycleError) Unwrap() error {
+	return container.ErrCycle
+}
+
+// frame is a vertex on the stack of an iterative depth first search, with the index of the
+// next arc to follow and the frame of the vertex it was reached from.
+type frame struct {
+	v      *vertex
+	i      int
+	parent *frame
+}
+
+// These constants denote the state of a vertex during depth first search.
+const (
+	unvisited = iota
+	onPath
+	finished
+)
+
+// TopologicalSort returns all vertices ordered so that every edge leads from a vertex to a later
+// one, in O(V + E) time. If there is none because the graph has a cycle, returns a *CycleError
+// holding one of the cycles. An undirected graph has a topological order only if it has no edges,
+// as every edge of it makes a cycle of two vertices.
+func (g *Graph) TopologicalSort() ([]interface{}, error) {
+	state := make([]int8, len(g.vertices))
+	order := make([]interface{}, len(g.vertices))
+	n := len(order)
+
+	ls := stack.NewLStack()
+	for _, root := range g.vertices {
+		if state[root.id] != unvisited {
+			continue
+		}
+
+		state[root.id] = onPath
+		ls.Push(&frame{v: root})
+		for !ls.Empty() {
+			f := ls.Pop().(*frame)
+			if f.i == len(f.v.arcs) {
+				// Vertices are finished in reverse topological order.
+				state[f.v.id] = finished
+				n--
+				order[n] = f.v.key
+				continue
+			}
+
+			w := f.v.arcs[f.i].to
+			f.i++
+			ls.Push(f)
+
+			switch state[w.id] {
+			case unvisited:
+				state[w.id] = onPath
+				ls.Push(&frame{v: w, parent: f})
+			case onPath:
+				var cycle []interface{}
+				for p := f; ; p = p.parent {
+					cycle = append(cycle, p.v.key)
+					if p.v == w {
+						break
+					}
+				}
+				for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return nil, &CycleError{cycle}
+			}
+		}
+	}
+	return order, nil
+}
+
+// HasCycle reports whether the graph has a cycle, which in an undirected graph is a cycle of at
+// least three vertices or a loop.
+func (g *Graph) HasCycle() bool {
+	if g.directed {
+		_, err := g.TopologicalSort()
+		return err != nil
+	}
+
+	// An undirected graph is a forest if and only if no edge joins vertices already connected.
+	ds := disjointset.NewDisjointSet(len(g.vertices))
+	for _, u := range g.vertices {
+		for _, a := range u.arcs {
+			if u.id <= a.to.id && !ds.Union(u.id, a.to.id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keys returns the vertices of ids.
+func (g *Graph) keys(ids []int) []interface{} {
+	ret := make([]interface{}, len(ids))
+	for i, id := range ids {
+		ret[i] = g.vertices[id].key
+	}
+	return ret
+}
+
+// ConnectedComponents returns the connected components of the graph, which for a directed graph
+// are its weakly connected components, ignoring the direction of the edges. Both the components
+// and the vertices of each of them are in order of insertion of the vertices.
+func (g *Graph) ConnectedComponents() [][]interface{} {
+	ds := disjointset.NewDisjointSet(len(g.vertices))
+	for _, u := range g.vertices {
+		for _, a := range u.arcs {
+			ds.Union(u.id, a.to.id)
+		}
+	}
+
+	sets := ds.Sets()
+	ret := make([][]interface{}, len(sets))
+	for i, set := range sets {
+		ret[i] = g.keys(set)
+	}
+	return ret
+}
+
+// StronglyConnectedComponents returns the strongly connected components of the graph, within
+// each of which every vertex can be reached from every other one, using Tarjan's algorithm in
+// O(V + E) time. The components are in reverse topological order, no edge leading from one of
+// them to a later one, and the vertices of each of them in order of insertion. For an undirected
+// graph they are the connected components.
+func (g *Graph) StronglyConnectedComponents() [][]interface{} {
+	n := len(g.vertices)
+	index, low := make([]int, n), make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+
+	var ret [][]interface{}
+	var next int
+	component := stack.NewLStack()
+	discover := func(v *vertex) {
+		index[v.id], low[v.id] = next, next
+		next++
+		component.Push(v)
+		onStack[v.id] = true
+	}
+
+	calls := stack.NewLStack()
+	for _, root := range g.vertices {
+		if index[root.id] != -1 {
+			continue
+		}
+
+		discover(root)
+		calls.Push(&frame{v: root})
+		for !calls.Empty() {
+			f := calls.Pop().(*frame)
+			v := f.v
+			if f.i < len(v.arcs) {
+				w := v.arcs[f.i].to
+				f.i++
+				calls.Push(f)
+				if index[w.id] == -1 {
+					discover(w)
+					calls.Push(&frame{v: w, parent: f})
+				} else if onStack[w.id] && index[w.id] < low[v.id] {
+					low[v.id] = index[w.id]
+				}
+				continue
+			}
+
+			if p := f.parent; p != nil && low[v.id] < low[p.v.id] {
+				low[p.v.id] = low[v.id]
+			}
+			if low[v.id] != index[v.id] {
+				continue
+			}
+
+			// v is the root of a component, which consists of the vertices above it on the stack.
+			var ids []int
+			for {
+				w := component.Pop().(*vertex)
+				onStack[w.id] = false
+				ids = append(ids, w.id)
+				if w == v {
+					break
+				}
+			}
+			sort.Ints(ids)
+			ret = append(ret, g.keys(ids))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,149 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/graph"
+)
+
+// reachable returns whether every vertex of g, numbered 0 to n-1, can reach every other one.
+func reachable(g *graph.Graph, n int) [][]bool {
+	reach := make([][]bool, n)
+	for v := range reach {
+		reach[v] = make([]bool, n)
+		it, _ := g.BFS(v)
+		for it.Next() {
+			reach[v][it.Vertex().(int)] = true
+		}
+	}
+	return reach
+}
+
+func TestGraphTopologicalSort(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		// Edges only lead from lower to higher vertices, so that the graph is acyclic.
+		g := graph.NewDirected()
+		for _, v := range r.Perm(30) {
+			g.AddVertex(v)
+		}
+		for k := 0; k < 60; k++ {
+			u, v := r.Intn(30), r.Intn(30)
+			if u < v {
+				g.AddEdge(u, v, 0)
+			}
+		}
+		if g.HasCycle() {
+			t.Fatalf("acyclic graph has a cycle")
+		}
+
+		order, err := g.TopologicalSort()
+		if err != nil || len(order) != 30 {
+			t.Fatalf("(%v != nil) or (%v != 30)", err, len(order))
+		}
+		pos := make(map[interface{}]int)
+		for i, v := range order {
+			pos[v] = i
+		}
+		for _, e := range g.Edges() {
+			if pos[e.From] >= pos[e.To] {
+				t.Fatalf("%v is not ordered before %v", e.From, e.To)
+			}
+		}
+
+		// Any edge back from higher to lower vertices connected by a path closes a cycle.
+		u, v := order[0], order[len(order)-1]
+		it, _ := g.BFS(u)
+		for it.Next() {
+			v = it.Vertex()
+		}
+		if u == v {
+			continue
+		}
+		g.AddEdge(v, u, 0)
+		_, err = g.TopologicalSort()
+		var ce *graph.CycleError
+		if !errors.As(err, &ce) || !errors.Is(err, container.ErrCycle) || !g.HasCycle() {
+			t.Fatalf("%v is not a cycle error", err)
+		}
+		for i, w := range ce.Cycle {
+			if next := ce.Cycle[(i+1)%len(ce.Cycle)]; !g.HasEdge(w, next) {
+				t.Fatalf("no edge from %v to %v in cycle %v", w, next, ce.Cycle)
+			}
+		}
+	}
+
+	g := graph.NewUndirected()
+	g.AddEdge(0, 1, 0)
+	g.AddEdge(1, 2, 0)
+	if _, err := g.TopologicalSort(); !errors.Is(err, container.ErrCycle) {
+		t.Errorf("%v != %v", err, container.ErrCycle)
+	}
+	if g.HasCycle() {
+		t.Errorf("path has a cycle")
+	}
+	g.AddEdge(2, 0, 0)
+	if !g.HasCycle() {
+		t.Errorf("triangle has no cycle")
+	}
+}
+
+func TestGraphComponents(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		const n = 40
+		g := randomGraph(directed, n, 45)
+		reach := reachable(g, n)
+
+		scc := g.StronglyConnectedComponents()
+		comp := make(map[int]int)
+		var count int
+		for i, c := range scc {
+			for j, v := range c {
+				if j > 0 && v.(int) <= c[j-1].(int) {
+					t.Fatalf("%v is not in order", c)
+				}
+				comp[v.(int)] = i
+				count++
+			}
+		}
+		if count != n {
+			t.Fatalf("%v != %v", count, n)
+		}
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				strong := reach[u][v] && reach[v][u]
+				if strong != (comp[u] == comp[v]) {
+					t.Fatalf("%v and %v: %v != %v", u, v, strong, comp[u] == comp[v])
+				}
+				// No edge leads to a later component.
+				if g.HasEdge(u, v) && comp[u] < comp[v] {
+					t.Fatalf("edge from %v to later component of %v", u, v)
+				}
+			}
+		}
+
+		cc := g.ConnectedComponents()
+		if !directed && len(cc) != len(scc) {
+			t.Errorf("%v != %v", len(cc), len(scc))
+		}
+		weak := make(map[int]int)
+		for i, c := range cc {
+			for _, v := range c {
+				weak[v.(int)] = i
+			}
+		}
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if (reach[u][v] || reach[v][u]) && weak[u] != weak[v] {
+					t.Fatalf("%v and %v are in different components", u, v)
+				}
+			}
+		}
+		for _, e := range g.Edges() {
+			if weak[e.From.(int)] != weak[e.To.(int)] {
+				t.Fatalf("edge %v joins different components", e)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/NzKSO/container"
+)
+
+// item is a vertex in the priority queue of a shortest path search, with the length of the path
+// it was reached by and the estimated length of the whole path through it.
+type item struct {
+	v        *vertex
+	dist     float64
+	estimate float64
+}
+
+// priorityQueue is a min-heap of items by estimate. It implements heap.Interface.
+type priorityQueue []item
+
+func (pq priorityQueue) Len() int {
+	return len(pq)
+}
+
+func (pq priorityQueue) Less(i, j int) bool {
+	return pq[i].estimate < pq[j].estimate
+}
+
+func (pq priorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+}
+
+func (pq *priorityQueue) Push(x interface{}) {
+	*pq = append(*pq, x.(item))
+}
+
+func (pq *priorityQueue) Pop() interface{} {
+	old := *pq
+	x := old[len(old)-1]
+	*pq = old[:len(old)-1]
+	return x
+}
+
+// search finds the shortest paths from s, stopping as soon as the one to goal is found unless
+// goal is nil. It returns the distance of every vertex, +Inf if it wasn't reached, and the vertex
+// each one was reached from. Vertices are queued by their distance plus h, so h = nil makes it
+// Dijkstra's algorithm and anything else A*. Rather than decreasing keys, a vertex is queued again
+// whenever a shorter path to it is found and stale items are skipped, which also keeps A* correct
+// for heuristics that are admissible but not consistent.
+func (g *Graph) search(s, goal *vertex, h func(v interface{}) float64) ([]float64, []*vertex, error) {
+	estimate := func(v *vertex, dist float64) float64 {
+		if h == nil {
+			return dist
+		}
+		return dist + h(v.key)
+	}
+
+	dist := make([]float64, len(g.vertices))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	parent := make([]*vertex, len(g.vertices))
+
+	dist[s.id] = 0
+	pq := priorityQueue{{s, 0, estimate(s, 0)}}
+	for len(pq) > 0 {
+		it := heap.Pop(&pq).(item)
+		v := it.v
+		if it.dist > dist[v.id] {
+			continue
+		}
+		if v == goal {
+			break
+		}
+
+		for _, a := range v.arcs {
+			if a.weight < 0 {
+				return nil, nil, container.ErrNegativeWeight
+			}
+			if d := dist[v.id] + a.weight; d < dist[a.to.id] {
+				dist[a.to.id] = d
+				parent[a.to.id] = v
+				heap.Push(&pq, item{a.to, d, estimate(a.to, d)})
+			}
+		}
+	}
+	return dist, parent, nil
+}
+
+// path returns the shortest path from from to to and its length, see ShortestPath.
+func (g *Graph) path(from, to interface{}, h func(v interface{}) float64) ([]interface{}, float64, error) {
+	s, t := g.index[from], g.index[to]
+	if s == nil || t == nil {
+		return nil, 0, container.ErrNotExist
+	}
+
+	dist, parent, err := g.search(s, t, h)
+	if err != nil {
+		return nil, 0, err
+	}
+	if math.IsInf(dist[t.id], 1) {
+		return nil, 0, container.ErrNoPath
+	}
+
+	var path []interface{}
+	for v := t; v != nil; v = parent[v.id] {
+		path = append(path, v.key)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, dist[t.id], nil
+}
+
+// ShortestPath returns the vertices on a shortest path from from to to, both included, and its
+// length, the sum of the weights of its edges, using Dijkstra's algorithm in O((V + E) log V) time.
+// If either vertex doesn't exist, returns ErrNotExist. If to can't be reached from from, returns
+// ErrNoPath. If the search reaches an edge of negative weight, returns ErrNegativeWeight.
+func (g *Graph) ShortestPath(from, to interface{}) ([]interface{}, float64, error) {
+	return g.path(from, to, nil)
+}
+
+// AStar returns a shortest path from from to to and its length the same way as ShortestPath, but
+// uses the A* algorithm, which searches toward to first as guided by the heuristic h. h(v) must
+// be admissible, never overestimating the length of the shortest path from v to to, for the path
+// found to be a shortest one, and the search is fastest if h is also consistent.
+func (g *Graph) AStar(from, to interface{}, h func(v interface{}) float64) ([]interface{}, float64, error) {
+	return g.path(from, to, h)
+}
+
+// Distances returns the lengths of the shortest paths from from to all vertices reachable from
+// it, including itself, using Dijkstra's algorithm. If from doesn't exist, returns ErrNotExist.
+// If an edge of negative weight is reached, returns ErrNegativeWeight.
+func (g *Graph) Distances(from interface{}) (map[interface{}]float64, error) {
+	s := g.index[from]
+	if s == nil {
+		return nil, container.ErrNotExist
+	}
+
+	dist, _, err := g.search(s, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[interface{}]float64)
+	for i, d := range dist {
+		if !math.IsInf(d, 1) {
+			ret[g.vertices[i].key] = d
+		}
+	}
+	return ret, nil
+}
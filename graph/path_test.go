@@ -0,0 +1,140 @@
+package graph_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/graph"
+)
+
+// floydWarshall returns the lengths of the shortest paths between all vertices 0 to n-1.
+func floydWarshall(g *graph.Graph, n int) [][]float64 {
+	dist := make([][]float64, n)
+	for u := range dist {
+		dist[u] = make([]float64, n)
+		for v := range dist[u] {
+			dist[u][v] = math.Inf(1)
+			if w, err := g.Weight(u, v); err == nil {
+				dist[u][v] = w
+			}
+		}
+		dist[u][u] = 0
+	}
+	for k := 0; k < n; k++ {
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if d := dist[u][k] + dist[k][v]; d < dist[u][v] {
+					dist[u][v] = d
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// pathLength returns the length of path, or -1 if an edge of it doesn't exist.
+func pathLength(g *graph.Graph, path []interface{}) float64 {
+	var length float64
+	for i := 1; i < len(path); i++ {
+		w, err := g.Weight(path[i-1], path[i])
+		if err != nil {
+			return -1
+		}
+		length += w
+	}
+	return length
+}
+
+func TestGraphShortestPath(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		const n = 30
+		g := randomGraph(directed, n, 70)
+		want := floydWarshall(g, n)
+
+		for u := 0; u < n; u++ {
+			dist, err := g.Distances(u)
+			if err != nil {
+				t.Fatalf("%v != nil", err)
+			}
+			for v := 0; v < n; v++ {
+				d, ok := dist[v]
+				if ok != !math.IsInf(want[u][v], 1) || ok && d != want[u][v] {
+					t.Fatalf("Distances(%v)[%v]: %v != %v", u, v, d, want[u][v])
+				}
+
+				path, length, err := g.ShortestPath(u, v)
+				if !ok {
+					if err != container.ErrNoPath {
+						t.Fatalf("%v != %v", err, container.ErrNoPath)
+					}
+					continue
+				}
+				if err != nil || length != want[u][v] || pathLength(g, path) != length || path[0] != u || path[len(path)-1] != v {
+					t.Fatalf("ShortestPath(%v, %v): %v of length %v, %v != %v", u, v, path, length, err, want[u][v])
+				}
+			}
+		}
+	}
+
+	g := graph.NewDirected()
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, -1)
+	if _, _, err := g.ShortestPath(0, 2); err != container.ErrNegativeWeight {
+		t.Errorf("%v != %v", err, container.ErrNegativeWeight)
+	}
+	if _, _, err := g.ShortestPath(0, 3); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if _, err := g.Distances(3); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if path, length, err := g.ShortestPath(1, 1); err != nil || length != 0 || !compareSlice(path, []interface{}{1}) {
+		t.Errorf("(%v != nil) or (%v != 0) or (%v != [1])", err, length, path)
+	}
+}
+
+func TestGraphAStar(t *testing.T) {
+	// A grid with random walls, whose cells are joined to their free neighbors by edges of
+	// weight 1 or 2, and a Manhattan distance heuristic, which is admissible.
+	const size = 20
+	type cell struct{ x, y int }
+	wall := func(c cell) bool { return (c.x*7+c.y*13)%5 == 0 && c.x%size != 0 }
+
+	g := graph.NewUndirected()
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			c := cell{x, y}
+			if wall(c) {
+				continue
+			}
+			for _, d := range []cell{{c.x + 1, c.y}, {c.x, c.y + 1}} {
+				if d.x < size && d.y < size && !wall(d) {
+					g.AddEdge(c, d, float64(1+r.Intn(2)))
+				}
+			}
+		}
+	}
+
+	from := cell{0, 0}
+	dist, _ := g.Distances(from)
+	for _, v := range g.Vertices() {
+		to := v.(cell)
+		h := func(v interface{}) float64 {
+			c := v.(cell)
+			return math.Abs(float64(c.x-to.x)) + math.Abs(float64(c.y-to.y))
+		}
+
+		path, length, err := g.AStar(from, to, h)
+		want, ok := dist[to]
+		if !ok {
+			if err != container.ErrNoPath {
+				t.Fatalf("%v != %v", err, container.ErrNoPath)
+			}
+			continue
+		}
+		if err != nil || length != want || pathLength(g, path) != length {
+			t.Fatalf("AStar(%v, %v): %v of length %v, %v != %v", from, to, path, length, err, want)
+		}
+	}
+}
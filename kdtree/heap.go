@@ -0,0 +1,28 @@
+package kdtree
+
+// neighborHeap is a max-heap of neighbors by distance, which keeps the k nearest neighbors
+// found so far with the farthest of them on top. It implements heap.Interface.
+type neighborHeap []Neighbor
+
+func (h neighborHeap) Len() int {
+	return len(h)
+}
+
+func (h neighborHeap) Less(i, j int) bool {
+	return h[i].Distance > h[j].Distance
+}
+
+func (h neighborHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *neighborHeap) Push(x interface{}) {
+	*h = append(*h, x.(Neighbor))
+}
+
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,466 @@
+// Package kdtree implements a k-d tree, a binary search tree of points in k-dimensional space
+// that splits the space along a different axis at every level, which answers nearest neighbour
+// and axis-aligned range queries without looking at most of the points.
+package kdtree
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/NzKSO/container"
+)
+
+// Point is a point in k-dimensional space, given by its coordinates along every axis.
+type Point []float64
+
+func (p Point) equal(q Point) bool {
+	for i := range p {
+		if p[i] != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Entry is a point stored in the tree together with its value.
+type Entry struct {
+	Point
+	Value interface{}
+}
+
+// Neighbor is an entry found by a nearest neighbour query, with its distance to the query point.
+type Neighbor struct {
+	Entry
+	Distance float64
+}
+
+type node struct {
+	entry       Entry
+	axis        int
+	left, right *node
+}
+
+// Option configures a KDTree.
+type Option func(kt *KDTree)
+
+// WithMetric makes the tree measure distances by m instead of Euclidean.
+func WithMetric(m Metric) Option {
+	return func(kt *KDTree) {
+		kt.metric = m
+	}
+}
+
+// KDTree represents a k-d tree of points with k = Dims. A node at depth d splits the space along
+// axis d mod k, its left subtree holding the points whose coordinate along that axis is less than
+// its own, and its right subtree the others. Points are unique, and the tree keeps its own copy of
+// every point inserted, but the points it returns must not be modified.
+type KDTree struct {
+	rw     sync.RWMutex
+	root   *node
+	dims   int
+	size   int
+	metric Metric
+}
+
+// NewKDTree returns an empty k-d tree of dims dimensions. It panics if dims is less than 1.
+func NewKDTree(dims int, opts ...Option) *KDTree {
+	if dims < 1 {
+		panic(fmt.Sprintf("kdtree: %d dimensions", dims))
+	}
+
+	kt := &KDTree{dims: dims, metric: Euclidean{}}
+	for _, opt := range opts {
+		opt(kt)
+	}
+	return kt
+}
+
+// Build returns a balanced k-d tree of dims dimensions holding entries, which is built in
+// O(n log^2 n) time by splitting every subtree at the median along its axis. If the point of
+// any entry doesn't have dims coordinates, returns ErrDimensionMismatch; if two entries have
+// the same point, returns ErrDataExists. It panics if dims is less than 1.
+func Build(dims int, entries []Entry, opts ...Option) (*KDTree, error) {
+	kt := NewKDTree(dims, opts...)
+
+	es := make([]Entry, len(entries))
+	for i, e := range entries {
+		if len(e.Point) != dims {
+			return nil, container.ErrDimensionMismatch
+		}
+		es[i] = Entry{append(Point(nil), e.Point...), e.Value}
+	}
+
+	sort.Slice(es, func(i, j int) bool {
+		p, q := es[i].Point, es[j].Point
+		for a := range p {
+			if p[a] != q[a] {
+				return p[a] < q[a]
+			}
+		}
+		return false
+	})
+	for i := 1; i < len(es); i++ {
+		if es[i].Point.equal(es[i-1].Point) {
+			return nil, container.ErrDataExists
+		}
+	}
+
+	kt.root = kt.build(es, 0)
+	kt.size = len(es)
+	return kt, nil
+}
+
+// build returns the root of a balanced subtree of entries at depth depth, reordering entries.
+func (kt *KDTree) build(entries []Entry, depth int) *node {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	axis := depth % kt.dims
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Point[axis] < entries[j].Point[axis]
+	})
+	// Points equal to the median along the axis belong to its right subtree.
+	m := len(entries) / 2
+	for m > 0 && entries[m-1].Point[axis] == entries[m].Point[axis] {
+		m--
+	}
+
+	return &node{
+		entry: entries[m],
+		axis:  axis,
+		left:  kt.build(entries[:m], depth+1),
+		right: kt.build(entries[m+1:], depth+1),
+	}
+}
+
+// Dims returns the number of dimensions of the tree.
+func (kt *KDTree) Dims() int {
+	return kt.dims
+}
+
+// locate returns the link to the node of point p, which points to nil if p doesn't exist, and
+// the depth of that link.
+func (kt *KDTree) locate(p Point) (**node, int) {
+	link, depth := &kt.root, 0
+	for n := *link; n != nil; n = *link {
+		if n.entry.Point.equal(p) {
+			break
+		}
+		if p[n.axis] < n.entry.Point[n.axis] {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+		depth++
+	}
+	return link, depth
+}
+
+// Insert inserts point p with value to the tree. If p doesn't have Dims coordinates, returns
+// ErrDimensionMismatch; if p already exists, returns ErrDataExists.
+func (kt *KDTree) Insert(p Point, value interface{}) error {
+	if len(p) != kt.dims {
+		return container.ErrDimensionMismatch
+	}
+
+	kt.rw.Lock()
+	defer kt.rw.Unlock()
+
+	link, depth := kt.locate(p)
+	if *link != nil {
+		return container.ErrDataExists
+	}
+	*link = &node{entry: Entry{append(Point(nil), p...), value}, axis: depth % kt.dims}
+	kt.size++
+	return nil
+}
+
+// Search returns the value of point p. If p doesn't have Dims coordinates, returns
+// ErrDimensionMismatch. If the tree is empty, returns ErrEmptyTree. If not found, returns ErrNotExist.
+func (kt *KDTree) Search(p Point) (interface{}, error) {
+	if len(p) != kt.dims {
+		return nil, container.ErrDimensionMismatch
+	}
+
+	kt.rw.RLock()
+	defer kt.rw.RUnlock()
+
+	if kt.size == 0 {
+		return nil, container.ErrEmptyTree
+	}
+	link, _ := kt.locate(p)
+	if *link == nil {
+		return nil, container.ErrNotExist
+	}
+	return (*link).entry.Value, nil
+}
+
+// Delete deletes point p. If p doesn't have Dims coordinates, returns ErrDimensionMismatch.
+// If the tree is empty, returns ErrEmptyTree. If not found, returns ErrNotExist.
+func (kt *KDTree) Delete(p Point) error {
+	if len(p) != kt.dims {
+		return container.ErrDimensionMismatch
+	}
+
+	kt.rw.Lock()
+	defer kt.rw.Unlock()
+
+	if kt.size == 0 {
+		return container.ErrEmptyTree
+	}
+	link, _ := kt.locate(p)
+	if *link == nil {
+		return container.ErrNotExist
+	}
+	remove(link)
+	kt.size--
+	return nil
+}
+
+// remove removes the node link points to. A node with a right subtree is replaced by the
+// minimum of that subtree along its axis, which keeps the left subtree less and the right one
+// no less than it. A node with only a left subtree first moves it to the right, since its
+// minimum can't be placed above data equal to it on the left.
+func remove(link **node) {
+	for {
+		n := *link
+		if n.right == nil {
+			if n.left == nil {
+				*link = nil
+				return
+			}
+			n.left, n.right = nil, n.left
+		}
+
+		link = minimum(&n.right, n.axis)
+		n.entry = (*link).entry
+	}
+}
+
+// minimum returns the link to the node with the least coordinate along axis in the subtree that
+// link points to, which must not be empty.
+func minimum(link **node, axis int) **node {
+	n := *link
+	if n.axis == axis {
+		if n.left == nil {
+			return link
+		}
+		return minimum(&n.left, axis)
+	}
+
+	min := link
+	for _, child := range []**node{&n.left, &n.right} {
+		if *child == nil {
+			continue
+		}
+		if m := minimum(child, axis); (*m).entry.Point[axis] < (*min).entry.Point[axis] {
+			min = m
+		}
+	}
+	return min
+}
+
+// Nearest returns the entry nearest to point p. If p doesn't have Dims coordinates, returns
+// ErrDimensionMismatch. If the tree is empty, returns ErrEmptyTree.
+func (kt *KDTree) Nearest(p Point) (Neighbor, error) {
+	nb, err := kt.KNearest(p, 1)
+	if err != nil {
+		return Neighbor{}, err
+	}
+	return nb[0], nil
+}
+
+// KNearest returns the k entries nearest to point p, or all of them if the tree has fewer, sorted
+// by distance with the nearest first. Ties are broken arbitrarily. If p doesn't have Dims
+// coordinates, returns ErrDimensionMismatch. If the tree is empty, returns ErrEmptyTree.
+func (kt *KDTree) KNearest(p Point, k int) ([]Neighbor, error) {
+	if len(p) != kt.dims {
+		return nil, container.ErrDimensionMismatch
+	}
+
+	kt.rw.RLock()
+	defer kt.rw.RUnlock()
+
+	if kt.size == 0 {
+		return nil, container.ErrEmptyTree
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+
+	h := make(neighborHeap, 0, k)
+	kt.nearest(kt.root, p, k, &h)
+
+	ret := make([]Neighbor, len(h))
+	for i := len(ret) - 1; i >= 0; i-- {
+		ret[i] = heap.Pop(&h).(Neighbor)
+	}
+	return ret, nil
+}
+
+// nearest adds the entries of the subtree of n that are nearer to p than the farthest of the k
+// nearest neighbors in h so far to h. The side of n that p lies on is searched first, so that the
+// other side can be skipped if the splitting plane is farther away than that neighbor.
+func (kt *KDTree) nearest(n *node, p Point, k int, h *neighborHeap) {
+	if n == nil {
+		return
+	}
+
+	d := kt.metric.Distance(p, n.entry.Point)
+	if len(*h) < k {
+		heap.Push(h, Neighbor{n.entry, d})
+	} else if d < (*h)[0].Distance {
+		(*h)[0] = Neighbor{n.entry, d}
+		heap.Fix(h, 0)
+	}
+
+	diff := p[n.axis] - n.entry.Point[n.axis]
+	near, far := n.left, n.right
+	if diff >= 0 {
+		near, far = far, near
+	}
+	kt.nearest(near, p, k, h)
+	if len(*h) < k || kt.metric.AxisDistance(diff) < (*h)[0].Distance {
+		kt.nearest(far, p, k, h)
+	}
+}
+
+// RangeSearch returns the entries within the axis-aligned box from lo to hi, boundary included,
+// in no particular order. If either corner doesn't have Dims coordinates, returns
+// ErrDimensionMismatch. If lo is greater than hi along any axis, returns ErrInvalidInterval.
+func (kt *KDTree) RangeSearch(lo, hi Point) ([]Entry, error) {
+	if len(lo) != kt.dims || len(hi) != kt.dims {
+		return nil, container.ErrDimensionMismatch
+	}
+	for a := range lo {
+		if lo[a] > hi[a] {
+			return nil, container.ErrInvalidInterval
+		}
+	}
+
+	kt.rw.RLock()
+	defer kt.rw.RUnlock()
+
+	var ret []Entry
+	kt.rangeSearch(kt.root, lo, hi, &ret)
+	return ret, nil
+}
+
+func (kt *KDTree) rangeSearch(n *node, lo, hi Point, ret *[]Entry) {
+	if n == nil {
+		return
+	}
+
+	inside := true
+	for a, v := range n.entry.Point {
+		if v < lo[a] || v > hi[a] {
+			inside = false
+			break
+		}
+	}
+	if inside {
+		*ret = append(*ret, n.entry)
+	}
+
+	v := n.entry.Point[n.axis]
+	if lo[n.axis] < v {
+		kt.rangeSearch(n.left, lo, hi, ret)
+	}
+	if hi[n.axis] >= v {
+		kt.rangeSearch(n.right, lo, hi, ret)
+	}
+}
+
+// Size returns the number of points in the tree.
+func (kt *KDTree) Size() int {
+	return kt.size
+}
+
+// Empty returns true if the tree is empty, otherwise false.
+func (kt *KDTree) Empty() bool {
+	return kt.size == 0
+}
+
+// Reset drops all of points in the tree and back to its initial state, keeping its dimensions
+// and metric.
+func (kt *KDTree) Reset() {
+	kt.rw.Lock()
+	kt.root = nil
+	kt.size = 0
+	kt.rw.Unlock()
+}
+
+// Height returns the number of edges on the longest path from the root to a leaf, or -1 if the
+// tree is empty.
+func (kt *KDTree) Height() int {
+	kt.rw.RLock()
+	defer kt.rw.RUnlock()
+
+	return height(kt.root)
+}
+
+func height(n *node) int {
+	if n == nil {
+		return -1
+	}
+	l, r := height(n.left), height(n.right)
+	if l > r {
+		return l + 1
+	}
+	return r + 1
+}
+
+// Validate checks that every node splits along the axis of its depth, that every point lies on
+// the correct side of the splitting planes of all its ancestors, and that the size is correct.
+// If not, returns an error wrapping ErrCorrupted.
+func (kt *KDTree) Validate() error {
+	kt.rw.RLock()
+	defer kt.rw.RUnlock()
+
+	count, err := kt.validate(kt.root, 0, nil)
+	if err != nil {
+		return err
+	}
+	if count != kt.size {
+		return fmt.Errorf("%w: %d points counted, size is %d", container.ErrCorrupted, count, kt.size)
+	}
+	return nil
+}
+
+// plane is a splitting plane of an ancestor, with the side of it the subtree lies on.
+type plane struct {
+	axis  int
+	v     float64
+	below bool
+}
+
+func (kt *KDTree) validate(n *node, depth int, planes []plane) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+
+	p := n.entry.Point
+	if len(p) != kt.dims || n.axis != depth%kt.dims {
+		return 0, fmt.Errorf("%w: node %v at depth %d splits along axis %d", container.ErrCorrupted, p, depth, n.axis)
+	}
+	for _, pl := range planes {
+		if (p[pl.axis] < pl.v) != pl.below {
+			return 0, fmt.Errorf("%w: %v is on the wrong side of %v along axis %d", container.ErrCorrupted, p, pl.v, pl.axis)
+		}
+	}
+
+	planes = append(planes, plane{n.axis, p[n.axis], true})
+	l, err := kt.validate(n.left, depth+1, planes)
+	if err != nil {
+		return 0, err
+	}
+	planes[len(planes)-1].below = false
+	r, err := kt.validate(n.right, depth+1, planes)
+	if err != nil {
+		return 0, err
+	}
+	return l + r + 1, nil
+}
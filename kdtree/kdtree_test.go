@@ -0,0 +1,238 @@
+package kdtree_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/kdtree"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// randomPoints returns n distinct points of dims coordinates on a small grid, so that many of
+// them share coordinates along some axis.
+func randomPoints(n, dims int) []kdtree.Point {
+	seen := make(map[[3]float64]bool)
+	var ps []kdtree.Point
+	for len(ps) < n {
+		var key [3]float64
+		p := make(kdtree.Point, dims)
+		for a := range p {
+			p[a] = float64(r.Intn(20))
+			key[a] = p[a]
+		}
+		if !seen[key] {
+			seen[key] = true
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+func entriesOf(ps []kdtree.Point) []kdtree.Entry {
+	es := make([]kdtree.Entry, len(ps))
+	for i, p := range ps {
+		es[i] = kdtree.Entry{Point: p, Value: i}
+	}
+	return es
+}
+
+// bruteKNearest returns the distances of the k nearest points to p.
+func bruteKNearest(m kdtree.Metric, ps []kdtree.Point, p kdtree.Point, k int) []float64 {
+	ds := make([]float64, len(ps))
+	for i, q := range ps {
+		ds[i] = m.Distance(p, q)
+	}
+	sort.Float64s(ds)
+	if k < len(ds) {
+		ds = ds[:k]
+	}
+	return ds
+}
+
+func TestKDTreeBuild(t *testing.T) {
+	ps := make([]kdtree.Point, 1000)
+	for i := range ps {
+		ps[i] = kdtree.Point{r.Float64(), r.Float64()}
+	}
+	kt, err := kdtree.Build(2, entriesOf(ps))
+	if err != nil || kt.Size() != len(ps) {
+		t.Fatalf("(%v != nil) or (%v != %v)", err, kt.Size(), len(ps))
+	}
+	if err := kt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if h := kt.Height(); h != 9 {
+		t.Errorf("%v != 9", h)
+	}
+	for i, p := range ps {
+		if v, err := kt.Search(p); err != nil || v != i {
+			t.Errorf("(%v != nil) or (%v != %v)", err, v, i)
+		}
+	}
+
+	// Build keeps its own copy of the points.
+	ps[0][0] = 2
+	if err := kt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	dup := entriesOf([]kdtree.Point{{1, 2}, {3, 4}, {1, 2}})
+	if _, err := kdtree.Build(2, dup); err != container.ErrDataExists {
+		t.Errorf("%v != %v", err, container.ErrDataExists)
+	}
+	if _, err := kdtree.Build(3, dup); err != container.ErrDimensionMismatch {
+		t.Errorf("%v != %v", err, container.ErrDimensionMismatch)
+	}
+	if kt, err := kdtree.Build(2, nil); err != nil || !kt.Empty() || kt.Height() != -1 {
+		t.Errorf("(%v != nil) or tree isn't empty", err)
+	}
+}
+
+func TestKDTreeInsertDelete(t *testing.T) {
+	kt := kdtree.NewKDTree(3)
+	if err := kt.Delete(kdtree.Point{0, 0, 0}); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+	if _, err := kt.Nearest(kdtree.Point{0, 0, 0}); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+
+	ps := randomPoints(500, 3)
+	for i, p := range ps {
+		if err := kt.Insert(p, i); err != nil {
+			t.Fatalf("%v != nil", err)
+		}
+	}
+	if err := kt.Insert(ps[0], 0); err != container.ErrDataExists {
+		t.Errorf("%v != %v", err, container.ErrDataExists)
+	}
+	if err := kt.Insert(kdtree.Point{1, 2}, 0); err != container.ErrDimensionMismatch {
+		t.Errorf("%v != %v", err, container.ErrDimensionMismatch)
+	}
+	if err := kt.Validate(); err != nil {
+		t.Fatalf("%v != nil", err)
+	}
+
+	r.Shuffle(len(ps), func(i, j int) { ps[i], ps[j] = ps[j], ps[i] })
+	for i, p := range ps {
+		if err := kt.Delete(p); err != nil {
+			t.Fatalf("%v != nil", err)
+		}
+		want := container.ErrNotExist
+		if kt.Empty() {
+			want = container.ErrEmptyTree
+		}
+		if err := kt.Delete(p); err != want {
+			t.Fatalf("%v != %v", err, want)
+		}
+		if i%25 == 0 {
+			if err := kt.Validate(); err != nil {
+				t.Fatalf("%v != nil", err)
+			}
+			for _, q := range ps[i+1:] {
+				if _, err := kt.Search(q); err != nil {
+					t.Fatalf("Search(%v): %v != nil", q, err)
+				}
+			}
+		}
+	}
+	if !kt.Empty() || kt.Validate() != nil {
+		t.Errorf("%v != 0", kt.Size())
+	}
+}
+
+func TestKDTreeKNearest(t *testing.T) {
+	ps := randomPoints(300, 3)
+	for _, m := range []kdtree.Metric{kdtree.Euclidean{}, kdtree.SquaredEuclidean{}, kdtree.Manhattan{}, kdtree.Chebyshev{}} {
+		kt, _ := kdtree.Build(3, entriesOf(ps), kdtree.WithMetric(m))
+		for n := 0; n < 50; n++ {
+			p := kdtree.Point{r.Float64() * 22, r.Float64() * 22, r.Float64() * 22}
+			k := r.Intn(10)
+			nb, err := kt.KNearest(p, k)
+			if err != nil {
+				t.Fatalf("%v != nil", err)
+			}
+
+			want := bruteKNearest(m, ps, p, k)
+			if len(nb) != len(want) {
+				t.Fatalf("%T: %v != %v", m, len(nb), len(want))
+			}
+			for i := range nb {
+				if nb[i].Distance != want[i] || m.Distance(p, nb[i].Point) != nb[i].Distance {
+					t.Fatalf("%T: %v != %v", m, nb[i].Distance, want[i])
+				}
+			}
+
+			near, _ := kt.Nearest(p)
+			if d := m.Distance(p, ps[near.Value.(int)]); near.Distance != d || d != bruteKNearest(m, ps, p, 1)[0] {
+				t.Fatalf("%T: %v is not the nearest distance", m, near.Distance)
+			}
+		}
+	}
+
+	kt, _ := kdtree.Build(3, entriesOf(ps))
+	if nb, _ := kt.KNearest(kdtree.Point{0, 0, 0}, 1000); len(nb) != len(ps) {
+		t.Errorf("%v != %v", len(nb), len(ps))
+	}
+	if nb, err := kt.Nearest(ps[7]); err != nil || nb.Distance != 0 || nb.Value != 7 {
+		t.Errorf("(%v != nil) or (%v != 0) or (%v != 7)", err, nb.Distance, nb.Value)
+	}
+}
+
+func TestKDTreeRangeSearch(t *testing.T) {
+	ps := randomPoints(400, 2)
+	kt := kdtree.NewKDTree(2)
+	for i, p := range ps {
+		kt.Insert(p, i)
+	}
+
+	for n := 0; n < 100; n++ {
+		lo := kdtree.Point{float64(r.Intn(20)), float64(r.Intn(20))}
+		hi := kdtree.Point{lo[0] + float64(r.Intn(8)), lo[1] + float64(r.Intn(8))}
+		got, err := kt.RangeSearch(lo, hi)
+		if err != nil {
+			t.Fatalf("%v != nil", err)
+		}
+
+		var want []int
+		for i, p := range ps {
+			if p[0] >= lo[0] && p[0] <= hi[0] && p[1] >= lo[1] && p[1] <= hi[1] {
+				want = append(want, i)
+			}
+		}
+		ids := make([]int, len(got))
+		for i, e := range got {
+			ids[i] = e.Value.(int)
+		}
+		sort.Ints(ids)
+		if len(ids) != len(want) {
+			t.Fatalf("%v != %v", ids, want)
+		}
+		for i := range ids {
+			if ids[i] != want[i] {
+				t.Fatalf("%v != %v", ids, want)
+			}
+		}
+	}
+
+	inf := math.Inf(1)
+	if all, _ := kt.RangeSearch(kdtree.Point{-inf, -inf}, kdtree.Point{inf, inf}); len(all) != len(ps) {
+		t.Errorf("%v != %v", len(all), len(ps))
+	}
+	if _, err := kt.RangeSearch(kdtree.Point{1, 1}, kdtree.Point{0, 2}); err != container.ErrInvalidInterval {
+		t.Errorf("%v != %v", err, container.ErrInvalidInterval)
+	}
+	if _, err := kt.RangeSearch(kdtree.Point{1}, kdtree.Point{0, 2}); err != container.ErrDimensionMismatch {
+		t.Errorf("%v != %v", err, container.ErrDimensionMismatch)
+	}
+
+	kt.Reset()
+	if got, err := kt.RangeSearch(kdtree.Point{-inf, -inf}, kdtree.Point{inf, inf}); err != nil || len(got) != 0 {
+		t.Errorf("(%v != nil) or (%v != 0)", err, len(got))
+	}
+}
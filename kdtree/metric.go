@@ -0,0 +1,80 @@
+package kdtree
+
+import "math"
+
+// Metric measures the distance between points. Besides Distance, it must provide AxisDistance,
+// the distance between two points whose coordinates differ by d along a single axis, which has to
+// be a lower bound of the distance between any two points separated by d along that axis, so that
+// the search can skip the far side of a splitting plane. All Minkowski distances satisfy this.
+type Metric interface {
+	Distance(a, b Point) float64
+	AxisDistance(d float64) float64
+}
+
+// Euclidean is the Metric of straight-line distance.
+type Euclidean struct{}
+
+// Distance returns the Euclidean distance between a and b.
+func (Euclidean) Distance(a, b Point) float64 {
+	return math.Sqrt(SquaredEuclidean{}.Distance(a, b))
+}
+
+// AxisDistance returns |d|.
+func (Euclidean) AxisDistance(d float64) float64 {
+	return math.Abs(d)
+}
+
+// SquaredEuclidean is the Metric of squared straight-line distance, which orders neighbours the
+// same way as Euclidean without taking square roots.
+type SquaredEuclidean struct{}
+
+// Distance returns the squared Euclidean distance between a and b.
+func (SquaredEuclidean) Distance(a, b Point) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// AxisDistance returns d squared.
+func (SquaredEuclidean) AxisDistance(d float64) float64 {
+	return d * d
+}
+
+// Manhattan is the Metric of the sum of the distances along every axis.
+type Manhattan struct{}
+
+// Distance returns the Manhattan distance between a and b.
+func (Manhattan) Distance(a, b Point) float64 {
+	var sum float64
+	for i := range a {
+		sum += math.Abs(a[i] - b[i])
+	}
+	return sum
+}
+
+// AxisDistance returns |d|.
+func (Manhattan) AxisDistance(d float64) float64 {
+	return math.Abs(d)
+}
+
+// Chebyshev is the Metric of the greatest distance along any axis.
+type Chebyshev struct{}
+
+// Distance returns the Chebyshev distance between a and b.
+func (Chebyshev) Distance(a, b Point) float64 {
+	var max float64
+	for i := range a {
+		if d := math.Abs(a[i] - b[i]); d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+// AxisDistance returns |d|.
+func (Chebyshev) AxisDistance(d float64) float64 {
+	return math.Abs(d)
+}
@@ -0,0 +1,286 @@
+// Package linkedmap implements a hash map that remembers the order in which its keys were
+// inserted, combining a Go map for O(1) lookup with a doubly linked list for ordered iteration.
+package linkedmap
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NzKSO/container"
+)
+
+// Entry is a key and its value, as sent by Traversal.
+type Entry struct {
+	Key, Value interface{}
+}
+
+type node struct {
+	Entry
+	prev, next *node
+}
+
+// LinkedHashMap represents a map of keys, which must be comparable as map keys are, kept in order
+// of insertion from the oldest to the newest. The nodes form a ring around a sentinel. Setting the
+// value of an existing key keeps its position, MoveToEnd makes it the newest. Every structural
+// modification of the map is counted, so that traversals can detect it.
+type LinkedHashMap struct {
+	rw    sync.RWMutex
+	index map[interface{}]*node
+	root  node
+	mods  uint64
+}
+
+// NewLinkedHashMap returns an empty map.
+func NewLinkedHashMap() *LinkedHashMap {
+	lm := &LinkedHashMap{}
+	lm.reset()
+	return lm
+}
+
+func (lm *LinkedHashMap) reset() {
+	lm.index = make(map[interface{}]*node)
+	lm.root.prev, lm.root.next = &lm.root, &lm.root
+	lm.mods++
+}
+
+// pushBack links n as the newest node.
+func (lm *LinkedHashMap) pushBack(n *node) {
+	n.prev, n.next = lm.root.prev, &lm.root
+	n.prev.next, n.next.prev = n, n
+}
+
+func (lm *LinkedHashMap) unlink(n *node) {
+	n.prev.next, n.next.prev = n.next, n.prev
+	n.prev, n.next = nil, nil
+}
+
+// Set sets the value of key. A new key is inserted as the newest one, an existing one keeps its
+// position. It reports whether key is new.
+func (lm *LinkedHashMap) Set(key, value interface{}) bool {
+	lm.rw.Lock()
+	defer lm.rw.Unlock()
+
+	if n := lm.index[key]; n != nil {
+		n.Value = value
+		return false
+	}
+
+	n := &node{Entry: Entry{key, value}}
+	lm.index[key] = n
+	lm.pushBack(n)
+	lm.mods++
+	return true
+}
+
+// Get returns the value of key. If the map is empty, returns ErrEmptyList. If key doesn't exist,
+// returns ErrNotExist.
+func (lm *LinkedHashMap) Get(key interface{}) (interface{}, error) {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	if len(lm.index) == 0 {
+		return nil, container.ErrEmptyList
+	}
+	n := lm.index[key]
+	if n == nil {
+		return nil, container.ErrNotExist
+	}
+	return n.Value, nil
+}
+
+// Contains reports whether key exists.
+func (lm *LinkedHashMap) Contains(key interface{}) bool {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	_, ok := lm.index[key]
+	return ok
+}
+
+// Delete deletes key. If the map is empty, returns ErrEmptyList. If key doesn't exist, returns
+// ErrNotExist.
+func (lm *LinkedHashMap) Delete(key interface{}) error {
+	lm.rw.Lock()
+	defer lm.rw.Unlock()
+
+	if len(lm.index) == 0 {
+		return container.ErrEmptyList
+	}
+	n := lm.index[key]
+	if n == nil {
+		return container.ErrNotExist
+	}
+
+	delete(lm.index, key)
+	lm.unlink(n)
+	lm.mods++
+	return nil
+}
+
+// MoveToEnd makes key the newest key, as if it was deleted and inserted again with the same value.
+// If the map is empty, returns ErrEmptyList. If key doesn't exist, returns ErrNotExist.
+func (lm *LinkedHashMap) MoveToEnd(key interface{}) error {
+	lm.rw.Lock()
+	defer lm.rw.Unlock()
+
+	if len(lm.index) == 0 {
+		return container.ErrEmptyList
+	}
+	n := lm.index[key]
+	if n == nil {
+		return container.ErrNotExist
+	}
+
+	if n.next != &lm.root {
+		lm.unlink(n)
+		lm.pushBack(n)
+		lm.mods++
+	}
+	return nil
+}
+
+// end returns the key and value of n, or ErrEmptyList if n is the sentinel.
+func (lm *LinkedHashMap) end(n *node) (interface{}, interface{}, error) {
+	if n == &lm.root {
+		return nil, nil, container.ErrEmptyList
+	}
+	return n.Key, n.Value, nil
+}
+
+// Oldest returns the key inserted first and its value. If the map is empty, returns ErrEmptyList.
+func (lm *LinkedHashMap) Oldest() (interface{}, interface{}, error) {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	return lm.end(lm.root.next)
+}
+
+// Newest returns the key inserted last and its value. If the map is empty, returns ErrEmptyList.
+func (lm *LinkedHashMap) Newest() (interface{}, interface{}, error) {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	return lm.end(lm.root.prev)
+}
+
+// Range calls visit with every key and its value from the oldest to the newest, until visit
+// returns false. visit runs with the map locked for reading, so it must not modify the map.
+func (lm *LinkedHashMap) Range(visit func(key, value interface{}) bool) {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	for n := lm.root.next; n != &lm.root; n = n.next {
+		if !visit(n.Key, n.Value) {
+			return
+		}
+	}
+}
+
+// Keys returns all keys from the oldest to the newest.
+func (lm *LinkedHashMap) Keys() []interface{} {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	keys := make([]interface{}, 0, len(lm.index))
+	for n := lm.root.next; n != &lm.root; n = n.next {
+		keys = append(keys, n.Key)
+	}
+	return keys
+}
+
+// Values returns the values of all keys from the oldest to the newest.
+func (lm *LinkedHashMap) Values() []interface{} {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	values := make([]interface{}, 0, len(lm.index))
+	for n := lm.root.next; n != &lm.root; n = n.next {
+		values = append(values, n.Value)
+	}
+	return values
+}
+
+// Traversal returns a received only channel, which receives an Entry for every key from the
+// oldest to the newest. If the map is structurally modified before the traversal ends,
+// ErrConcurrentModification is sent as the last value.
+func (lm *LinkedHashMap) Traversal() <-chan interface{} {
+	ch := make(chan interface{})
+
+	lm.rw.RLock()
+	mods := lm.mods
+	lm.rw.RUnlock()
+
+	go func() {
+		defer close(ch)
+
+		// The map is only locked while walking, not while blocked on sending.
+		lm.rw.RLock()
+		for n := lm.root.next; n != &lm.root; {
+			if lm.mods != mods {
+				lm.rw.RUnlock()
+				ch <- container.ErrConcurrentModification
+				return
+			}
+
+			e := n.Entry
+			n = n.next
+			lm.rw.RUnlock()
+			ch <- e
+			lm.rw.RLock()
+		}
+		if lm.mods != mods {
+			lm.rw.RUnlock()
+			ch <- container.ErrConcurrentModification
+			return
+		}
+		lm.rw.RUnlock()
+	}()
+
+	return ch
+}
+
+// Size returns the number of keys in the map.
+func (lm *LinkedHashMap) Size() int {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	return len(lm.index)
+}
+
+// Empty returns true if the map is empty, otherwise false.
+func (lm *LinkedHashMap) Empty() bool {
+	return lm.Size() == 0
+}
+
+// Reset drops all keys in the map and back to its initial state.
+func (lm *LinkedHashMap) Reset() {
+	lm.rw.Lock()
+	lm.reset()
+	lm.rw.Unlock()
+}
+
+// Validate checks the invariants of the map: the links of every node agree in both directions,
+// every node is indexed by its key, and the list has as many nodes as the index. It returns nil
+// if the map is consistent, otherwise an error wrapping ErrCorrupted.
+func (lm *LinkedHashMap) Validate() error {
+	lm.rw.RLock()
+	defer lm.rw.RUnlock()
+
+	var count int
+	for n := lm.root.next; n != &lm.root; n = n.next {
+		if n.next.prev != n || n.prev.next != n {
+			return fmt.Errorf("%w: links of %v disagree", container.ErrCorrupted, n.Key)
+		}
+		if lm.index[n.Key] != n {
+			return fmt.Errorf("%w: %v is not indexed", container.ErrCorrupted, n.Key)
+		}
+		count++
+		if count > len(lm.index) {
+			return fmt.Errorf("%w: list is longer than index of %d keys", container.ErrCorrupted, len(lm.index))
+		}
+	}
+	if count != len(lm.index) {
+		return fmt.Errorf("%w: list has %d nodes, index has %d keys", container.ErrCorrupted, count, len(lm.index))
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package linkedmap_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/linkedmap"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func compareSlice(s1, s2 []interface{}) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if s1[i] != s2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLinkedHashMapBasic(t *testing.T) {
+	lm := linkedmap.NewLinkedHashMap()
+	if _, err := lm.Get("a"); err != container.ErrEmptyList {
+		t.Errorf("%v != %v", err, container.ErrEmptyList)
+	}
+	if _, _, err := lm.Oldest(); err != container.ErrEmptyList {
+		t.Errorf("%v != %v", err, container.ErrEmptyList)
+	}
+	if err := lm.MoveToEnd("a"); err != container.ErrEmptyList {
+		t.Errorf("%v != %v", err, container.ErrEmptyList)
+	}
+
+	for i, key := range []string{"c", "a", "d", "b"} {
+		if !lm.Set(key, i) {
+			t.Errorf("%v is not new", key)
+		}
+	}
+	if lm.Set("a", 10) {
+		t.Errorf("a is new")
+	}
+	if v, err := lm.Get("a"); v != 10 || err != nil {
+		t.Errorf("(%v != 10) or (%v != nil)", v, err)
+	}
+	if _, err := lm.Get("e"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if keys := lm.Keys(); !compareSlice(keys, []interface{}{"c", "a", "d", "b"}) {
+		t.Errorf("%v != [c a d b]", keys)
+	}
+	if values := lm.Values(); !compareSlice(values, []interface{}{0, 10, 2, 3}) {
+		t.Errorf("%v != [0 10 2 3]", values)
+	}
+
+	if err := lm.MoveToEnd("c"); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if err := lm.MoveToEnd("e"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if k, v, err := lm.Oldest(); k != "a" || v != 10 || err != nil {
+		t.Errorf("(%v != a) or (%v != 10) or (%v != nil)", k, v, err)
+	}
+	if k, v, err := lm.Newest(); k != "c" || v != 0 || err != nil {
+		t.Errorf("(%v != c) or (%v != 0) or (%v != nil)", k, v, err)
+	}
+
+	if err := lm.Delete("d"); err != nil || lm.Contains("d") {
+		t.Errorf("%v != nil", err)
+	}
+	if err := lm.Delete("d"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	var visited []interface{}
+	lm.Range(func(key, value interface{}) bool {
+		visited = append(visited, key)
+		return len(visited) < 2
+	})
+	if !compareSlice(visited, []interface{}{"a", "b"}) {
+		t.Errorf("%v != [a b]", visited)
+	}
+	if lm.Size() != 3 || lm.Empty() || lm.Validate() != nil {
+		t.Errorf("%v != 3", lm.Size())
+	}
+
+	lm.Reset()
+	if !lm.Empty() || len(lm.Keys()) != 0 || lm.Validate() != nil {
+		t.Errorf("%v != 0", lm.Size())
+	}
+}
+
+func TestLinkedHashMapRandom(t *testing.T) {
+	lm := linkedmap.NewLinkedHashMap()
+	var order []int
+	values := make(map[int]int)
+	indexOf := func(key int) int {
+		for i, k := range order {
+			if k == key {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for i := 0; i < 3000; i++ {
+		key := r.Intn(100)
+		pos := indexOf(key)
+		switch r.Intn(4) {
+		case 0:
+			if err := lm.Delete(key); (err == nil) != (pos != -1) {
+				t.Fatalf("Delete(%v): %v", key, err)
+			}
+			if pos != -1 {
+				order = append(order[:pos], order[pos+1:]...)
+				delete(values, key)
+			}
+		case 1:
+			if err := lm.MoveToEnd(key); (err == nil) != (pos != -1) {
+				t.Fatalf("MoveToEnd(%v): %v", key, err)
+			}
+			if pos != -1 {
+				order = append(append(order[:pos], order[pos+1:]...), key)
+			}
+		default:
+			v := r.Int()
+			if lm.Set(key, v) != (pos == -1) {
+				t.Fatalf("Set(%v) disagrees on new key", key)
+			}
+			if pos == -1 {
+				order = append(order, key)
+			}
+			values[key] = v
+		}
+	}
+
+	if err := lm.Validate(); err != nil {
+		t.Fatalf("%v != nil", err)
+	}
+	var n int
+	for itf := range lm.Traversal() {
+		e := itf.(linkedmap.Entry)
+		if e.Key != order[n] || e.Value != values[order[n]] {
+			t.Fatalf("%v != {%v %v}", e, order[n], values[order[n]])
+		}
+		n++
+	}
+	if n != len(order) || lm.Size() != len(order) {
+		t.Errorf("(%v != %v) or (%v != %v)", n, len(order), lm.Size(), len(order))
+	}
+}
+
+func TestLinkedHashMapTraversalModified(t *testing.T) {
+	lm := linkedmap.NewLinkedHashMap()
+	for i := 0; i < 10; i++ {
+		lm.Set(i, i)
+	}
+
+	var last interface{}
+	var n int
+	for itf := range lm.Traversal() {
+		if n == 3 {
+			lm.MoveToEnd(0)
+		}
+		last = itf
+		n++
+	}
+	if last != container.ErrConcurrentModification {
+		t.Errorf("%v != %v", last, container.ErrConcurrentModification)
+	}
+
+	// Setting the value of an existing key isn't a structural modification.
+	n = 0
+	for itf := range lm.Traversal() {
+		if _, ok := itf.(linkedmap.Entry); !ok {
+			t.Errorf("%v is not an entry", itf)
+		}
+		lm.Set(5, 50)
+		n++
+	}
+	if n != 10 {
+		t.Errorf("%v != 10", n)
+	}
+}
@@ -3,6 +3,8 @@
 package list
 
 import (
+	"fmt"
+	"runtime/debug"
 	"sync"
 
 	"github.com/NzKSO/container"
@@ -24,16 +26,37 @@ func (pnode *Node) GetData() interface{} {
 	return pnode.data
 }
 
-// SinglyList represents a singly linked list.
+// SinglyList represents a singly linked list. Every structural modification of the list
+// is counted, so that traversals running in other goroutines can detect it.
+//
+// Once sorted by Sort, the list stays sorted as long as it's only modified by Delete, Update,
+// ReKey, and Insert of data that Less orders before the head.
 type SinglyList struct {
 	rw              sync.RWMutex
 	head            *Node
 	size            int
+	mods            uint64
+	sorted          bool
 	NumPerGoroutine int // specify every how many nodes of list start a goroutine
+	checker         *container.Checker
+}
+
+// Option configures a SinglyList created by NewSinglyList.
+type Option func(*SinglyList)
+
+// WithChecker makes the list report every comparison between stored values to c, which is
+// meant for debugging Less and Find implementations. Comparisons with keys that don't implement
+// container.Interface, such as plain search keys, can't be checked and are reported as violations
+// of container.RuleKeyType.
+func WithChecker(c *container.Checker) Option {
+	return func(ll *SinglyList) {
+		ll.checker = c
+	}
 }
 
 type findResult struct {
 	prev, find *Node
+	err        error
 }
 
 type splitResult struct {
@@ -43,18 +66,36 @@ type splitResult struct {
 // SortFunc represents the type of sorting method implemented by the user.
 type SortFunc func(head *Node, size ...int)
 
-// NewSinglyList returns a pointer to linked list.
-func NewSinglyList() *SinglyList {
-	return &SinglyList{}
+// NewSinglyList returns a pointer to linked list configured by opts.
+func NewSinglyList(opts ...Option) *SinglyList {
+	ll := &SinglyList{}
+	for _, opt := range opts {
+		opt(ll)
+	}
+	return ll
+}
+
+// observe reports the comparison of itf with key to the checker of ll, if any. Keys that can't
+// be checked are recorded by the checker as violations, so the error of Observe adds nothing.
+func (ll *SinglyList) observe(itf container.Interface, key interface{}) {
+	if ll.checker != nil {
+		ll.checker.Observe(itf, key)
+	}
 }
 
 // Insert inserts data into linked list.
 func (ll *SinglyList) Insert(data container.Interface) {
 	newNode := new(Node)
 	newNode.data = data
+	ll.rw.Lock()
+	if ll.sorted && ll.head != nil && !data.Less(ll.head.data) {
+		ll.sorted = false
+	}
 	newNode.next = ll.head
 	ll.head = newNode
 	ll.size++
+	ll.mods++
+	ll.rw.Unlock()
 }
 
 func (ll *SinglyList) splitList() <-chan *splitResult {
@@ -103,36 +144,43 @@ func (ll *SinglyList) splitList() <-chan *splitResult {
 	return ch
 }
 
-// Delete deletes data specified by key from linked list.
+// Delete deletes data specified by key from linked list. If Find panics, Delete returns
+// a *container.CallbackPanic and leaves the list unchanged.
 func (ll *SinglyList) Delete(key interface{}) error {
+	ll.rw.Lock()
+	defer ll.rw.Unlock()
+
 	if ll.size == 0 && ll.head == nil {
 		return container.ErrEmptyList
 	}
 
-	res := ll.multiGoroutinesFind(ll.splitList(), key)
+	res, err := ll.multiGoroutinesFind(ll.splitList(), key)
+	if err != nil {
+		return err
+	}
 
 	if res != nil {
-		if res.prev == nil {
-			ll.head = res.find.next
-			ll.size--
-			return nil
-		}
-		ll.rw.Lock()
-		res.prev.next = res.find.next
-		ll.rw.Unlock()
+		ll.unlink(res)
 		ll.size--
 		return nil
 	}
 	return container.ErrNotExist
 }
 
-// Search searches data associated with key by lanuching multiple goroutines
+// Search searches data associated with key by lanuching multiple goroutines. If Find panics
+// in any of them, Search returns a *container.CallbackPanic.
 func (ll *SinglyList) Search(key interface{}) (interface{}, error) {
+	ll.rw.RLock()
+	defer ll.rw.RUnlock()
+
 	if ll.head == nil && ll.size == 0 {
 		return nil, container.ErrEmptyList
 	}
 
-	res := ll.multiGoroutinesFind(ll.splitList(), key)
+	res, err := ll.multiGoroutinesFind(ll.splitList(), key)
+	if err != nil {
+		return nil, err
+	}
 
 	if res != nil {
 		return res.find.data, nil
@@ -140,22 +188,30 @@ func (ll *SinglyList) Search(key interface{}) (interface{}, error) {
 	return nil, container.ErrNotExist
 }
 
-func (ll *SinglyList) multiGoroutinesFind(splitCh <-chan *splitResult, key interface{}) *findResult {
-	findResCh := make(chan *findResult)
-	termGoroutineCh := make(chan struct{}, 1)
+// multiGoroutinesFind searches every split in its own goroutine. A panic raised by Find in
+// any of them is recovered and returned as a *container.CallbackPanic. Every split is searched
+// to its end or its first match, so that the result doesn't depend on scheduling: a panic takes
+// precedence over any match, and among several panics or matches the one of the first split wins.
+// The caller must hold the lock, for writing if it modifies the list depending on the result.
+func (ll *SinglyList) multiGoroutinesFind(splitCh <-chan *splitResult, key interface{}) (*findResult, error) {
+	var splits []*splitResult
+	for split := range splitCh {
+		splits = append(splits, split)
+	}
 
 	var wg sync.WaitGroup
-
-	for split := range splitCh {
+	results := make([]*findResult, len(splits))
+	for i, split := range splits {
 		wg.Add(1)
-		go func(split *splitResult) {
+		go func(split *splitResult, res **findResult) {
 			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					*res = &findResult{err: &container.CallbackPanic{Value: r, Stack: debug.Stack()}}
+				}
+			}()
 
-			walk := split.head
-			ll.rw.RLock()
-			end := split.tail
-			ll.rw.RUnlock()
-
+			walk, end := split.head, split.tail
 			var (
 				prev *Node
 				itf  container.Interface
@@ -163,74 +219,154 @@ func (ll *SinglyList) multiGoroutinesFind(splitCh <-chan *splitResult, key inter
 
 			for walk != end {
 				itf = walk.data.(container.Interface)
+				ll.observe(itf, key)
 				if itf.Find(key) {
+					*res = &findResult{prev: prev, find: walk}
 					if walk == split.head {
-						findResCh <- &findResult{split.prev, walk}
-						return
+						(*res).prev = split.prev
 					}
-					findResCh <- &findResult{prev, walk}
-					return
-				}
-				select {
-				case <-termGoroutineCh:
 					return
-				default:
-					prev = walk
-					ll.rw.RLock()
-					walk = walk.next
-					ll.rw.RUnlock()
 				}
+				prev = walk
+				walk = walk.next
 			}
-		}(split)
+		}(split, &results[i])
 	}
+	wg.Wait()
 
-	go func() {
-		defer close(findResCh)
-		wg.Wait()
-	}()
-
-	for {
-		res, ok := <-findResCh
-		if ok {
-			termGoroutineCh <- struct{}{}
-			return res
+	var found *findResult
+	for _, res := range results {
+		if res == nil {
+			continue
+		}
+		if res.err != nil {
+			return res, res.err
+		}
+		if found == nil {
+			found = res
 		}
-		return res
 	}
+	return found, nil
 }
 
-// Update updates data associated with key in linked list.
+// Update updates data associated with key in linked list. If Find panics, Update returns
+// a *container.CallbackPanic. Set must not change the key of the data, use ReKey for that.
+// If it does, Update returns ErrKeyChanged, having moved the data to keep the list sorted.
 func (ll *SinglyList) Update(key interface{}, val interface{}) error {
+	ll.rw.Lock()
+	defer ll.rw.Unlock()
+
 	if ll.head == nil && ll.size == 0 {
 		return container.ErrEmptyList
 	}
 
-	res := ll.multiGoroutinesFind(ll.splitList(), key)
-	if res.find != nil {
+	res, err := ll.multiGoroutinesFind(ll.splitList(), key)
+	if err != nil {
+		return err
+	}
+
+	if res != nil {
 		itf := res.find.data.(container.Interface)
 		itf.Set(val)
-		return nil
+		if itf.Find(key) {
+			return nil
+		}
+
+		if ll.sorted {
+			ll.unlink(res)
+			ll.sortedInsert(res.find)
+		}
+		return container.ErrKeyChanged
 	}
 
 	return container.ErrNotExist
 }
 
+// ReKey replaces the data found by oldKey with newValue, which may have a different key. If the
+// list is sorted, newValue is moved to keep it sorted, unless its key is used by data other than
+// the one being replaced, in which case ReKey returns ErrDataExists. If Find panics, ReKey returns
+// a *container.CallbackPanic. The list is only changed if ReKey returns nil.
+func (ll *SinglyList) ReKey(oldKey interface{}, newValue container.Interface) error {
+	ll.rw.Lock()
+	defer ll.rw.Unlock()
+
+	if ll.head == nil && ll.size == 0 {
+		return container.ErrEmptyList
+	}
+
+	res, err := ll.multiGoroutinesFind(ll.splitList(), oldKey)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return container.ErrNotExist
+	}
+
+	if ll.sorted {
+		other, err := ll.multiGoroutinesFind(ll.splitList(), newValue)
+		if err != nil {
+			return err
+		}
+		if other != nil && other.find != res.find {
+			return container.ErrDataExists
+		}
+	}
+
+	res.find.data = newValue
+	if ll.sorted {
+		ll.unlink(res)
+		ll.sortedInsert(res.find)
+	}
+	return nil
+}
+
+// unlink removes the node found by res from the list, the caller must hold the write lock.
+func (ll *SinglyList) unlink(res *findResult) {
+	if res.prev == nil {
+		ll.head = res.find.next
+	} else {
+		res.prev.next = res.find.next
+	}
+	res.find.next = nil
+	ll.mods++
+}
+
+// sortedInsert puts newNode before the first node that Less doesn't order before it, the
+// caller must hold the write lock.
+func (ll *SinglyList) sortedInsert(newNode *Node) {
+	sortedInsert(&ll.head, newNode)
+	ll.mods++
+}
+
 // Traversal returns a received only channel, which can be used to receive results
-// that returned by traversing linked list.
+// that returned by traversing linked list. If the list is structurally modified before
+// the traversal ends, ErrConcurrentModification is sent as the last value.
 func (ll *SinglyList) Traversal() <-chan interface{} {
+	ll.rw.RLock()
+	mods := ll.mods
 	ch := make(chan interface{}, ll.size)
+	ll.rw.RUnlock()
+
 	go func() {
 		defer close(ch)
 
-		if ll.head == nil && ll.size == 0 {
-			return
-		}
-
+		// The list is only locked while walking, not while blocked on sending.
+		ll.rw.RLock()
 		walk := ll.head
 		for walk != nil {
-			ch <- walk.data
+			if ll.mods != mods {
+				ll.rw.RUnlock()
+				ch <- container.ErrConcurrentModification
+				return
+			}
+
+			data := walk.data
 			walk = walk.next
+			ll.rw.RUnlock()
+			ch <- data
+			ll.rw.RLock()
 		}
+		ll.rw.RUnlock()
 	}()
 
 	return ch
@@ -245,9 +381,7 @@ func (ll *SinglyList) reverse(split *splitResult, wg *sync.WaitGroup) {
 
 	for move != split.tail {
 		temp = move.next
-		ll.rw.Lock()
 		move.next = prev
-		ll.rw.Unlock()
 		prev = move
 		move = temp
 	}
@@ -257,11 +391,17 @@ func (ll *SinglyList) reverse(split *splitResult, wg *sync.WaitGroup) {
 	}
 }
 
-// Reverse reverses the list concurrently.
+// Reverse reverses the list concurrently. Each goroutine rewires its own part of the list,
+// and the list stays locked until all of them are done.
 func (ll *SinglyList) Reverse() {
+	ll.rw.Lock()
+	defer ll.rw.Unlock()
+
 	if ll.head == nil || ll.head.next == nil {
 		return
 	}
+	ll.mods++
+	ll.sorted = false
 
 	var wg sync.WaitGroup
 	for split := range ll.splitList() {
@@ -283,8 +423,58 @@ func (ll *SinglyList) Size() int {
 
 // Reset resets ll to its initial state, it will drop all of data.
 func (ll *SinglyList) Reset() {
+	ll.rw.Lock()
 	ll.head = nil
 	ll.size = 0
+	ll.mods++
+	ll.sorted = false
+	ll.rw.Unlock()
+}
+
+// Clone returns a list holding the same data in the same order as ll that shares no nodes with it.
+// The data are copied by copier, or shared between both lists if copier is nil, in which case Update
+// on either list is seen by the other. The clone keeps NumPerGoroutine and the checker of ll, and is
+// sorted if ll is.
+func (ll *SinglyList) Clone(copier container.Copier) *SinglyList {
+	ll.rw.RLock()
+	defer ll.rw.RUnlock()
+
+	clone := &SinglyList{
+		size:            ll.size,
+		sorted:          ll.sorted,
+		NumPerGoroutine: ll.NumPerGoroutine,
+		checker:         ll.checker,
+	}
+	for link, pnode := &clone.head, ll.head; pnode != nil; pnode = pnode.next {
+		data := pnode.data
+		if copier != nil {
+			data = copier(data.(container.Interface))
+		}
+		*link = &Node{data: data}
+		link = &(*link).next
+	}
+	return clone
+}
+
+// Validate checks the invariants of the list: following next from head never revisits a node,
+// and size equals the number of nodes. It returns nil if the list is consistent, otherwise an
+// error wrapping ErrCorrupted.
+func (ll *SinglyList) Validate() error {
+	ll.rw.RLock()
+	defer ll.rw.RUnlock()
+
+	visited := make(map[*Node]bool)
+	for walk := ll.head; walk != nil; walk = walk.next {
+		if visited[walk] {
+			return fmt.Errorf("%w: list has a cycle at %v", container.ErrCorrupted, walk.data)
+		}
+		visited[walk] = true
+	}
+
+	if len(visited) != ll.size {
+		return fmt.Errorf("%w: size is %d but list has %d nodes", container.ErrCorrupted, ll.size, len(visited))
+	}
+	return nil
 }
 
 // BubbleSort represents Bubble sorting, which can be used as parameter to method SortWith.
@@ -323,10 +513,16 @@ func BubbleSort(head *Node) {
 
 // Sort sorts the list using merge sorting by default
 func (ll *SinglyList) Sort() {
+	ll.rw.Lock()
+	defer ll.rw.Unlock()
+	ll.sorted = true
+
 	if ll.head == nil || ll.head.next == nil {
 		return
 	}
-	mergeSort(&ll.head)
+
+	ll.mods++
+	ll.mergeSort(&ll.head)
 }
 
 func getMiddleNode(head *Node) *Node {
@@ -340,7 +536,7 @@ func getMiddleNode(head *Node) *Node {
 	return slow
 }
 
-func mergeSort(phead **Node) {
+func (ll *SinglyList) mergeSort(phead **Node) {
 	if *phead == nil || (*phead).next == nil {
 		return
 	}
@@ -351,13 +547,13 @@ func mergeSort(phead **Node) {
 	back = middle.next
 	middle.next = nil
 
-	mergeSort(&front)
-	mergeSort(&back)
+	ll.mergeSort(&front)
+	ll.mergeSort(&back)
 
-	*phead = mergeList(front, back)
+	*phead = ll.mergeList(front, back)
 }
 
-func mergeList(front, back *Node) *Node {
+func (ll *SinglyList) mergeList(front, back *Node) *Node {
 	var head *Node
 
 	if front == nil {
@@ -366,14 +562,15 @@ func mergeList(front, back *Node) *Node {
 		return front
 	}
 
-	itf := front.data.(container.Lesser)
+	itf := front.data.(container.Interface)
+	ll.observe(itf, back.data)
 	ret := itf.Less(back.data)
 	if ret {
 		head = front
-		head.next = mergeList(front.next, back)
+		head.next = ll.mergeList(front.next, back)
 	} else {
 		head = back
-		head.next = mergeList(front, back.next)
+		head.next = ll.mergeList(front, back.next)
 	}
 
 	return head
@@ -414,5 +611,10 @@ func (ll *SinglyList) SortWith(sort SortFunc) {
 	if ll.head == nil || ll.head.next == nil {
 		return
 	}
+
+	ll.rw.Lock()
+	defer ll.rw.Unlock()
+	ll.mods++
+	ll.sorted = false
 	sort(ll.head, ll.size)
 }
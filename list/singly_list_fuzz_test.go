@@ -0,0 +1,101 @@
+package list_test
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/NzKSO/container/list"
+	"github.com/NzKSO/container/testdata"
+)
+
+// FuzzSinglyList decodes ops as a sequence of (opcode, key) byte pairs, applies them to a
+// SinglyList and to a slice used as reference model, and checks that both agree after every step.
+// The first byte selects NumPerGoroutine so that the concurrent paths are exercised as well.
+func FuzzSinglyList(f *testing.F) {
+	f.Add([]byte{0, 0, 5, 0, 3, 0, 7, 1, 3, 3, 0, 4, 0, 0})
+	f.Add([]byte{2, 0, 1, 0, 2, 0, 3, 0, 4, 0, 5, 3, 0, 1, 3, 4, 0, 2, 4, 1, 1})
+	f.Add([]byte{3, 0, 9, 0, 8, 0, 7, 0, 6, 0, 5, 0, 4, 0, 3, 4, 0, 3, 0, 1, 6, 2, 6})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		if len(ops) == 0 {
+			return
+		}
+
+		ll := list.NewSinglyList()
+		ll.NumPerGoroutine = int(ops[0] % 8)
+		var model []*testdata.Corp
+
+		indexOf := func(key int) int {
+			for i, c := range model {
+				if c.ID == key {
+					return i
+				}
+			}
+			return -1
+		}
+
+		for i := 1; i+1 < len(ops); i += 2 {
+			key := int(ops[i+1] % 32)
+			switch ops[i] % 5 {
+			case 0:
+				// keys are kept unique, Delete and Update pick an arbitrary match otherwise.
+				if indexOf(key) >= 0 {
+					continue
+				}
+				c := &testdata.Corp{ID: key, Name: strconv.Itoa(i)}
+				ll.Insert(c)
+				model = append([]*testdata.Corp{c}, model...)
+			case 1:
+				err := ll.Delete(key)
+				if idx := indexOf(key); idx >= 0 {
+					if err != nil {
+						t.Fatalf("Delete(%v): %v != nil", key, err)
+					}
+					model = append(model[:idx], model[idx+1:]...)
+				} else if err == nil {
+					t.Fatalf("Delete(%v) of missing key succeeded", key)
+				}
+			case 2:
+				name := "updated" + strconv.Itoa(i)
+				err := ll.Update(key, name)
+				if idx := indexOf(key); idx >= 0 {
+					if err != nil {
+						t.Fatalf("Update(%v): %v != nil", key, err)
+					}
+					if model[idx].Name != name {
+						t.Fatalf("%v != %v", model[idx].Name, name)
+					}
+				} else if err == nil {
+					t.Fatalf("Update(%v) of missing key succeeded", key)
+				}
+			case 3:
+				ll.Reverse()
+				for l, r := 0, len(model)-1; l < r; l, r = l+1, r-1 {
+					model[l], model[r] = model[r], model[l]
+				}
+			case 4:
+				ll.Sort()
+				sort.Slice(model, func(l, r int) bool { return model[l].ID < model[r].ID })
+			}
+
+			if err := ll.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			if ll.Size() != len(model) {
+				t.Fatalf("%v != %v", ll.Size(), len(model))
+			}
+			var j int
+			for itf := range ll.Traversal() {
+				if j >= len(model) || itf.(*testdata.Corp) != model[j] {
+					t.Fatalf("unexpected %v at position %v", itf, j)
+				}
+				j++
+			}
+			if j != len(model) {
+				t.Fatalf("%v != %v", j, len(model))
+			}
+		}
+	})
+}
@@ -1,8 +1,10 @@
 package list_test
 
 import (
+	"errors"
 	"math/rand"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -194,3 +196,339 @@ func TestListSort(t *testing.T) {
 		ID++
 	}
 }
+
+func TestListValidate(t *testing.T) {
+	ll := list.NewSinglyList()
+	if err := ll.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	ri := r.Perm(len(testdata.TestCases))
+	ll = createAndFillList(ri)
+	ll.NumPerGoroutine = 7
+	ll.Reverse()
+	ll.Sort()
+	for _, iv := range ri[:len(ri)/2] {
+		ll.Delete(testdata.TestCases[iv].ID)
+	}
+	if err := ll.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestListWithChecker(t *testing.T) {
+	c := container.NewChecker(0)
+	ll := list.NewSinglyList(list.WithChecker(c))
+	ll.NumPerGoroutine = 5
+	for _, iv := range r.Perm(len(testdata.TestCases)) {
+		ll.Insert(&testdata.TestCases[iv])
+	}
+	ll.Sort()
+	for _, iv := range r.Perm(len(testdata.TestCases)) {
+		ll.Search(&testdata.TestCases[iv])
+	}
+
+	if violations := c.Violations(); violations != nil {
+		t.Errorf("%v != nil", violations)
+	}
+}
+
+// fragile panics when asked to find a key it doesn't know.
+type fragile struct {
+	testdata.Corp
+}
+
+func (f *fragile) Find(key interface{}) bool {
+	return f.ID == key.(int)
+}
+
+func TestListCallbackPanic(t *testing.T) {
+	ll := list.NewSinglyList()
+	ll.NumPerGoroutine = 4
+	for _, iv := range r.Perm(len(testdata.TestCases)) {
+		ll.Insert(&fragile{testdata.TestCases[iv]})
+	}
+
+	if _, err := ll.Search("key"); !errors.Is(err, container.ErrCallbackPanic) {
+		t.Errorf("%v != %v", err, container.ErrCallbackPanic)
+	}
+	if err := ll.Update("key", "val"); !errors.Is(err, container.ErrCallbackPanic) {
+		t.Errorf("%v != %v", err, container.ErrCallbackPanic)
+	}
+	if err := ll.Delete("key"); !errors.Is(err, container.ErrCallbackPanic) {
+		t.Errorf("%v != %v", err, container.ErrCallbackPanic)
+	}
+
+	var cp *container.CallbackPanic
+	if _, err := ll.Search("key"); !errors.As(err, &cp) || len(cp.Stack) == 0 {
+		t.Errorf("%v doesn't carry the stack", err)
+	}
+
+	if ll.Size() != len(testdata.TestCases) {
+		t.Errorf("%v != %v", ll.Size(), len(testdata.TestCases))
+	}
+	if itf, err := ll.Search(testdata.TestCases[0].ID); err != nil || itf.(*fragile).Corp != testdata.TestCases[0] {
+		t.Errorf("(%v != nil) or (%v != %v)", err, itf, testdata.TestCases[0])
+	}
+}
+
+// bomb panics whenever Find is called.
+type bomb struct {
+	testdata.Corp
+}
+
+func (b *bomb) Find(key interface{}) bool {
+	panic("bomb")
+}
+
+func TestListCallbackPanicPrecedence(t *testing.T) {
+	ll := list.NewSinglyList()
+	ll.NumPerGoroutine = 4
+	ll.Insert(&bomb{testdata.TestCases[len(testdata.TestCases)-1]})
+	for i := 1; i < len(testdata.TestCases)-1; i++ {
+		ll.Insert(&fragile{testdata.TestCases[i]})
+	}
+	ll.Insert(&testdata.TestCases[0])
+
+	// The data found is at the head and the bomb at the tail of the list, so they are searched
+	// in different goroutines, and the panic wins even though it comes last.
+	key := testdata.TestCases[0].ID
+	for i := 0; i < 100; i++ {
+		if _, err := ll.Search(key); !errors.Is(err, container.ErrCallbackPanic) {
+			t.Fatalf("%v != %v", err, container.ErrCallbackPanic)
+		}
+	}
+	if err := ll.Delete(key); !errors.Is(err, container.ErrCallbackPanic) || ll.Size() != len(testdata.TestCases) {
+		t.Errorf("(%v != %v) or (%v != %v)", err, container.ErrCallbackPanic, ll.Size(), len(testdata.TestCases))
+	}
+}
+
+// TestListReverseRace runs Reverse alongside traversals, which must each see the list either
+// before or after a reversal, and is meant to be run with -race.
+func TestListReverseRace(t *testing.T) {
+	ri := r.Perm(len(testdata.TestCases))
+	ll := createAndFillList(ri)
+	ll.NumPerGoroutine = 4
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			ll.Reverse()
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+
+		var seq []*testdata.Corp
+		for itf := range ll.Traversal() {
+			if itf == container.ErrConcurrentModification {
+				break
+			}
+			seq = append(seq, itf.(*testdata.Corp))
+		}
+		if len(seq) < 2 {
+			continue
+		}
+
+		// The first two data tell the order in which the rest must follow.
+		j := 0
+		for j < len(ri) && &testdata.TestCases[ri[j]] != seq[0] {
+			j++
+		}
+		step := 1
+		if j+1 >= len(ri) || &testdata.TestCases[ri[j+1]] != seq[1] {
+			step = -1
+		}
+		for k, c := range seq {
+			if j < 0 || j >= len(ri) || c != &testdata.TestCases[ri[j]] {
+				t.Fatalf("unexpected %v at position %v", c, k)
+			}
+			j += step
+		}
+	}
+}
+
+func TestListTraversalConcurrentModification(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		ri := r.Perm(len(testdata.TestCases))
+		ll := createAndFillList(ri)
+		ll.NumPerGoroutine = 8
+
+		// Whether Reverse happens before, during or after the traversal is up to the
+		// scheduler, but the traversal never mixes up both orders.
+		ch := ll.Traversal()
+		ll.Reverse()
+
+		j := len(ri) - 1
+		for itf := range ch {
+			if itf == container.ErrConcurrentModification {
+				if _, ok := <-ch; ok {
+					t.Errorf("channel is not closed")
+				}
+				break
+			}
+			if itf.(*testdata.Corp) != &testdata.TestCases[ri[j]] {
+				t.Fatalf("%v != %v", itf, &testdata.TestCases[ri[j]])
+			}
+			j--
+		}
+	}
+}
+
+func checkSorted(t *testing.T, ll *list.SinglyList, ids []int) {
+	var got []int
+	for itf := range ll.Traversal() {
+		got = append(got, itf.(*testdata.IDSetter).ID)
+	}
+	if len(got) != len(ids) {
+		t.Fatalf("%v != %v", got, ids)
+	}
+	for i := range ids {
+		if got[i] != ids[i] {
+			t.Fatalf("%v != %v", got, ids)
+		}
+	}
+}
+
+func TestListUpdateKeyChanged(t *testing.T) {
+	ll := list.NewSinglyList()
+	for _, id := range []int{5, 3, 7, 1} {
+		ll.Insert(&testdata.IDSetter{testdata.Corp{ID: id}})
+	}
+	ll.Sort()
+
+	if err := ll.Update(3, 6); err != container.ErrKeyChanged {
+		t.Errorf("%v != %v", err, container.ErrKeyChanged)
+	}
+	checkSorted(t, ll, []int{1, 5, 6, 7})
+
+	if err := ll.ReKey(1, &testdata.IDSetter{testdata.Corp{ID: 8}}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	checkSorted(t, ll, []int{5, 6, 7, 8})
+
+	// Inserting at the head in order keeps the list sorted.
+	ll.Insert(&testdata.IDSetter{testdata.Corp{ID: 0}})
+	if err := ll.ReKey(0, &testdata.IDSetter{testdata.Corp{ID: 9}}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	checkSorted(t, ll, []int{5, 6, 7, 8, 9})
+
+	// The key of other data can't be taken in a sorted list.
+	if err := ll.ReKey(5, &testdata.IDSetter{testdata.Corp{ID: 9}}); err != container.ErrDataExists {
+		t.Errorf("%v != %v", err, container.ErrDataExists)
+	}
+	checkSorted(t, ll, []int{5, 6, 7, 8, 9})
+
+	// Once unsorted, data is updated in place.
+	ll.Reverse()
+	if err := ll.ReKey(9, &testdata.IDSetter{testdata.Corp{ID: 4}}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	checkSorted(t, ll, []int{4, 8, 7, 6, 5})
+	if err := ll.ReKey(9, &testdata.IDSetter{testdata.Corp{ID: 4}}); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if err := ll.Validate(); err != nil || ll.Size() != 5 {
+		t.Errorf("(%v != nil) or (%v != 5)", err, ll.Size())
+	}
+}
+
+// TestListConcurrentDelete deletes and inserts data from several goroutines, which must each find
+// and unlink their node atomically, and is meant to be run with -race.
+func TestListConcurrentDelete(t *testing.T) {
+	ll := list.NewSinglyList()
+	ll.NumPerGoroutine = 4
+	const n = 200
+	for id := 0; id < n; id++ {
+		ll.Insert(&testdata.Corp{ID: id})
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for id := g; id < n; id += 4 {
+				if err := ll.Delete(id); err != nil {
+					t.Errorf("%v != nil", err)
+				}
+				ll.Insert(&testdata.Corp{ID: n + id})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if err := ll.Validate(); err != nil || ll.Size() != n {
+		t.Errorf("(%v != nil) or (%v != %v)", err, ll.Size(), n)
+	}
+	for id := 0; id < 2*n; id++ {
+		if _, err := ll.Search(id); (err == nil) != (id >= n) {
+			t.Errorf("%v: %v", id, err)
+		}
+	}
+}
+
+func TestListClone(t *testing.T) {
+	ri := r.Perm(len(testdata.TestCases))
+	ll := createAndFillList(ri)
+	ll.NumPerGoroutine = 6
+
+	shallow := ll.Clone(nil)
+	deep := ll.Clone(func(data container.Interface) container.Interface {
+		c := *data.(*testdata.Corp)
+		return &c
+	})
+	for _, clone := range []*list.SinglyList{shallow, deep} {
+		if clone.Size() != ll.Size() || clone.NumPerGoroutine != ll.NumPerGoroutine {
+			t.Errorf("(%v != %v) or (%v != %v)", clone.Size(), ll.Size(), clone.NumPerGoroutine, ll.NumPerGoroutine)
+		}
+		if err := clone.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+
+	// Structural changes to the original don't reach the clones.
+	ll.Reverse()
+	ll.Delete(testdata.TestCases[ri[0]].ID)
+	i := len(ri) - 1
+	for itf := range deep.Traversal() {
+		if *itf.(*testdata.Corp) != testdata.TestCases[ri[i]] || itf.(*testdata.Corp) == &testdata.TestCases[ri[i]] {
+			t.Errorf("%v != %v or data is shared", itf, testdata.TestCases[ri[i]])
+		}
+		i--
+	}
+	if shallow.Size() != len(ri) {
+		t.Errorf("%v != %v", shallow.Size(), len(ri))
+	}
+
+	// Payloads are shared only with the shallow clone.
+	saved := testdata.TestCases[ri[1]].Name
+	defer func() { testdata.TestCases[ri[1]].Name = saved }()
+	ll.Update(testdata.TestCases[ri[1]].ID, "changed")
+	if itf, _ := shallow.Search(testdata.TestCases[ri[1]].ID); itf.(*testdata.Corp).Name != "changed" {
+		t.Errorf("%v != changed", itf)
+	}
+	if itf, _ := deep.Search(testdata.TestCases[ri[1]].ID); itf.(*testdata.Corp).Name != saved {
+		t.Errorf("%v != %v", itf, saved)
+	}
+
+	ll.Sort()
+	sorted := ll.Clone(nil)
+	if err := sorted.ReKey(testdata.TestCases[ri[1]].ID, &testdata.Corp{ID: 1000}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	var last int
+	for itf := range sorted.Traversal() {
+		last = itf.(*testdata.Corp).ID
+	}
+	if last != 1000 {
+		t.Errorf("%v != 1000", last)
+	}
+}
@@ -70,3 +70,12 @@ func (lq *LQueue) Reset() {
 func (lq *LQueue) Empty() bool {
 	return lq.size == 0 && lq.head == nil && lq.tail == nil
 }
+
+// Clone returns a copy of the LQueue, which shares the data but not the nodes with lq.
+func (lq *LQueue) Clone() *LQueue {
+	clone := NewLQueue()
+	for n := lq.head; n != nil; n = n.next {
+		clone.EnQueue(n.data)
+	}
+	return clone
+}
@@ -62,3 +62,26 @@ func TestLReset(t *testing.T) {
 		t.Errorf("LQueue is empty? %v", lq.Empty())
 	}
 }
+
+func TestLQueueClone(t *testing.T) {
+	lq := createLQueue()
+	c := lq.Clone()
+
+	lq.LeQueue()
+	lq.EnQueue("entered")
+	if c.Size() != len(testdata.TestCases) {
+		t.Errorf("%v != %v", c.Size(), len(testdata.TestCases))
+	}
+	for _, v := range testdata.TestCases {
+		lv := c.LeQueue()
+		if v != lv {
+			t.Errorf("%v != %v", v, lv)
+		}
+	}
+	if !c.Empty() || lq.Size() != len(testdata.TestCases) {
+		t.Errorf("clone is not empty or %v != %v", lq.Size(), len(testdata.TestCases))
+	}
+	if !queue.NewLQueue().Clone().Empty() {
+		t.Errorf("clone of empty queue is not empty")
+	}
+}
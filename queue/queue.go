@@ -46,3 +46,11 @@ func (q *Queue) Reset() {
 func (q *Queue) Empty() bool {
 	return len(q.data) == 0
 }
+
+// Clone returns a copy of the Queue, which shares the data but not the underlying array with q.
+func (q *Queue) Clone() *Queue {
+	if q.data == nil {
+		return &Queue{}
+	}
+	return &Queue{append([]interface{}(nil), q.data...)}
+}
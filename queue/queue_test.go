@@ -50,3 +50,26 @@ func TestReset(t *testing.T) {
 		t.Errorf("LQueue is empty? %v", q.Empty())
 	}
 }
+
+func TestQueueClone(t *testing.T) {
+	q := createQueue()
+	c := q.Clone()
+
+	q.LeQueue()
+	q.EnQueue("entered")
+	if c.Size() != len(testdata.TestCases) {
+		t.Errorf("%v != %v", c.Size(), len(testdata.TestCases))
+	}
+	for _, v := range testdata.TestCases {
+		lv := c.LeQueue()
+		if v != lv {
+			t.Errorf("%v != %v", v, lv)
+		}
+	}
+	if !c.Empty() || q.Size() != len(testdata.TestCases) {
+		t.Errorf("clone is not empty or %v != %v", q.Size(), len(testdata.TestCases))
+	}
+	if !queue.NewQueue().Clone().Empty() {
+		t.Errorf("clone of empty queue is not empty")
+	}
+}
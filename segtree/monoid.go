@@ -0,0 +1,103 @@
+package segtree
+
+import "math"
+
+// Sum is a Monoid of int64 values aggregated by addition.
+type Sum struct{}
+
+// Identity returns 0.
+func (Sum) Identity() interface{} {
+	return int64(0)
+}
+
+// Combine returns a + b.
+func (Sum) Combine(a, b interface{}) interface{} {
+	return a.(int64) + b.(int64)
+}
+
+// Min is a Monoid of int64 values aggregated by their minimum.
+type Min struct{}
+
+// Identity returns math.MaxInt64.
+func (Min) Identity() interface{} {
+	return int64(math.MaxInt64)
+}
+
+// Combine returns the less of a and b.
+func (Min) Combine(a, b interface{}) interface{} {
+	if b.(int64) < a.(int64) {
+		return b
+	}
+	return a
+}
+
+// Max is a Monoid of int64 values aggregated by their maximum.
+type Max struct{}
+
+// Identity returns math.MinInt64.
+func (Max) Identity() interface{} {
+	return int64(math.MinInt64)
+}
+
+// Combine returns the greater of a and b.
+func (Max) Combine(a, b interface{}) interface{} {
+	if b.(int64) > a.(int64) {
+		return b
+	}
+	return a
+}
+
+// Add is an Action that adds an int64 to every value of a range. Scale must be set for trees
+// aggregated by Sum, whose aggregates grow by the update times the number of values, and unset
+// for trees aggregated by Min or Max.
+type Add struct {
+	Scale bool
+}
+
+// NoOp returns 0.
+func (Add) NoOp() interface{} {
+	return int64(0)
+}
+
+// Compose returns f + g.
+func (Add) Compose(f, g interface{}) interface{} {
+	return f.(int64) + g.(int64)
+}
+
+// Apply returns the aggregate agg after f is added to each of its n values.
+func (a Add) Apply(f, agg interface{}, n int) interface{} {
+	if a.Scale {
+		return agg.(int64) + f.(int64)*int64(n)
+	}
+	return agg.(int64) + f.(int64)
+}
+
+// Assign is an Action that sets every value of a range to an int64, nil being the update that
+// changes nothing. Scale must be set the same way as for Add.
+type Assign struct {
+	Scale bool
+}
+
+// NoOp returns nil.
+func (Assign) NoOp() interface{} {
+	return nil
+}
+
+// Compose returns f, or g if f is nil.
+func (Assign) Compose(f, g interface{}) interface{} {
+	if f == nil {
+		return g
+	}
+	return f
+}
+
+// Apply returns the aggregate of n values all set to f, or agg if f is nil.
+func (a Assign) Apply(f, agg interface{}, n int) interface{} {
+	if f == nil {
+		return agg
+	}
+	if a.Scale {
+		return f.(int64) * int64(n)
+	}
+	return f
+}
@@ -0,0 +1,260 @@
+// Package segtree implements a segment tree, which aggregates any range of an array under a
+// monoid in O(log n) time, and optionally applies updates to whole ranges lazily.
+package segtree
+
+import (
+	"fmt"
+
+	"github.com/NzKSO/container"
+)
+
+// Monoid describes how values are aggregated. Combine must be associative and Identity must
+// be its identity element, but Combine needn't be commutative, the aggregate of a range being
+// the values combined from left to right.
+type Monoid interface {
+	Identity() interface{}
+	Combine(a, b interface{}) interface{}
+}
+
+// Action describes updates applied to every value of a range, such as adding a constant. NoOp
+// is the update that changes nothing, Compose(f, g) is the update that applies g and then f,
+// and Apply(f, agg, n) returns the aggregate of n values after f is applied to each of them,
+// given their aggregate agg before it. Apply must distribute over Combine.
+type Action interface {
+	NoOp() interface{}
+	Compose(f, g interface{}) interface{}
+	Apply(f, agg interface{}, n int) interface{}
+}
+
+// Option configures a SegTree.
+type Option func(st *SegTree)
+
+// WithAction makes the tree accept range updates described by a, which enables Update. Every
+// node then keeps the update pending for its children, which are pushed down when visited.
+func WithAction(a Action) Option {
+	return func(st *SegTree) {
+		st.action = a
+	}
+}
+
+// SegTree represents an array of n values indexed from 0 to n-1. Node x of the underlying
+// arrays covers a range of the values, and its children 2x and 2x+1 cover the left and right
+// half of it, the root 1 covering all of them. SegTree is not safe for concurrent use.
+type SegTree struct {
+	n      int
+	monoid Monoid
+	action Action
+	agg    []interface{}
+	lazy   []interface{} // nil without action
+}
+
+// NewSegTree returns a segment tree of values aggregated by m, which is built in O(n) time.
+func NewSegTree(values []interface{}, m Monoid, opts ...Option) *SegTree {
+	st := &SegTree{n: len(values), monoid: m}
+	for _, opt := range opts {
+		opt(st)
+	}
+
+	st.agg = make([]interface{}, 4*st.n)
+	if st.action != nil {
+		st.lazy = make([]interface{}, len(st.agg))
+		for i := range st.lazy {
+			st.lazy[i] = st.action.NoOp()
+		}
+	}
+	if st.n > 0 {
+		st.build(1, 0, st.n, values)
+	}
+	return st
+}
+
+func (st *SegTree) build(x, lo, hi int, values []interface{}) {
+	if hi-lo == 1 {
+		st.agg[x] = values[lo]
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	st.build(2*x, lo, mid, values)
+	st.build(2*x+1, mid, hi, values)
+	st.pull(x)
+}
+
+// pull recomputes the aggregate of node x from its children.
+func (st *SegTree) pull(x int) {
+	st.agg[x] = st.monoid.Combine(st.agg[2*x], st.agg[2*x+1])
+}
+
+// apply applies f to all n values covered by node x.
+func (st *SegTree) apply(x, n int, f interface{}) {
+	st.agg[x] = st.action.Apply(f, st.agg[x], n)
+	if n > 1 {
+		st.lazy[x] = st.action.Compose(f, st.lazy[x])
+	}
+}
+
+// push applies the update pending at node x, covering [lo, hi), to its children.
+func (st *SegTree) push(x, lo, hi int) {
+	if st.lazy == nil {
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	st.apply(2*x, mid-lo, st.lazy[x])
+	st.apply(2*x+1, hi-mid, st.lazy[x])
+	st.lazy[x] = st.action.NoOp()
+}
+
+// Len returns the number of values.
+func (st *SegTree) Len() int {
+	return st.n
+}
+
+func (st *SegTree) checkRange(lo, hi int) {
+	if lo < 0 || hi > st.n || lo > hi {
+		panic(fmt.Sprintf("segtree: range [%d, %d) out of range [0, %d)", lo, hi, st.n))
+	}
+}
+
+// Query returns the aggregate of the values in [lo, hi), which is Identity if lo == hi. It
+// panics if the range is invalid.
+func (st *SegTree) Query(lo, hi int) interface{} {
+	st.checkRange(lo, hi)
+	if lo == hi {
+		return st.monoid.Identity()
+	}
+	return st.query(1, 0, st.n, lo, hi)
+}
+
+func (st *SegTree) query(x, l, r, lo, hi int) interface{} {
+	if lo <= l && r <= hi {
+		return st.agg[x]
+	}
+
+	st.push(x, l, r)
+	mid := l + (r-l)/2
+	switch {
+	case hi <= mid:
+		return st.query(2*x, l, mid, lo, hi)
+	case lo >= mid:
+		return st.query(2*x+1, mid, r, lo, hi)
+	}
+	return st.monoid.Combine(st.query(2*x, l, mid, lo, hi), st.query(2*x+1, mid, r, lo, hi))
+}
+
+// Get returns the value at i. It panics if i is out of range.
+func (st *SegTree) Get(i int) interface{} {
+	st.checkRange(i, i+1)
+	return st.query(1, 0, st.n, i, i+1)
+}
+
+// Set sets the value at i to v. It panics if i is out of range.
+func (st *SegTree) Set(i int, v interface{}) {
+	st.checkRange(i, i+1)
+	st.set(1, 0, st.n, i, v)
+}
+
+func (st *SegTree) set(x, l, r, i int, v interface{}) {
+	if r-l == 1 {
+		st.agg[x] = v
+		return
+	}
+
+	st.push(x, l, r)
+	if mid := l + (r-l)/2; i < mid {
+		st.set(2*x, l, mid, i, v)
+	} else {
+		st.set(2*x+1, mid, r, i, v)
+	}
+	st.pull(x)
+}
+
+// Update applies f to every value in [lo, hi) in O(log n) time. If the tree was created
+// without WithAction, returns ErrNotAugmented. It panics if the range is invalid.
+func (st *SegTree) Update(lo, hi int, f interface{}) error {
+	if st.action == nil {
+		return container.ErrNotAugmented
+	}
+
+	st.checkRange(lo, hi)
+	if lo < hi {
+		st.update(1, 0, st.n, lo, hi, f)
+	}
+	return nil
+}
+
+func (st *SegTree) update(x, l, r, lo, hi int, f interface{}) {
+	if lo <= l && r <= hi {
+		st.apply(x, r-l, f)
+		return
+	}
+
+	st.push(x, l, r)
+	mid := l + (r-l)/2
+	if lo < mid {
+		st.update(2*x, l, mid, lo, hi, f)
+	}
+	if hi > mid {
+		st.update(2*x+1, mid, r, lo, hi, f)
+	}
+	st.pull(x)
+}
+
+// MaxRight returns the greatest hi such that pred holds for the aggregate of [lo, hi), given
+// that pred holds for Identity and that once it fails for a range, it fails for all ranges
+// extending it. It takes O(log n) time and panics if lo is out of range.
+func (st *SegTree) MaxRight(lo int, pred func(agg interface{}) bool) int {
+	st.checkRange(lo, lo)
+	if lo == st.n {
+		return st.n
+	}
+
+	acc := st.monoid.Identity()
+	hi, _ := st.maxRight(1, 0, st.n, lo, pred, &acc)
+	return hi
+}
+
+// maxRight returns where pred first fails within [max(l, lo), r) of node x, and whether it
+// does, combining the aggregates of the range passed into acc.
+func (st *SegTree) maxRight(x, l, r, lo int, pred func(interface{}) bool, acc *interface{}) (int, bool) {
+	if r <= lo {
+		return r, false
+	}
+	if lo <= l {
+		if next := st.monoid.Combine(*acc, st.agg[x]); pred(next) {
+			*acc = next
+			return r, false
+		}
+		if r-l == 1 {
+			return l, true
+		}
+	}
+
+	st.push(x, l, r)
+	mid := l + (r-l)/2
+	if hi, failed := st.maxRight(2*x, l, mid, lo, pred, acc); failed {
+		return hi, true
+	}
+	return st.maxRight(2*x+1, mid, r, lo, pred, acc)
+}
+
+// Values returns all values in order, with all pending updates applied.
+func (st *SegTree) Values() []interface{} {
+	values := make([]interface{}, 0, st.n)
+	if st.n > 0 {
+		st.collect(1, 0, st.n, &values)
+	}
+	return values
+}
+
+func (st *SegTree) collect(x, l, r int, values *[]interface{}) {
+	if r-l == 1 {
+		*values = append(*values, st.agg[x])
+		return
+	}
+
+	st.push(x, l, r)
+	mid := l + (r-l)/2
+	st.collect(2*x, l, mid, values)
+	st.collect(2*x+1, mid, r, values)
+}
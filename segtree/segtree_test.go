@@ -0,0 +1,147 @@
+package segtree_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/segtree"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// concat joins strings in order, Combine isn't commutative.
+type concat struct{}
+
+func (concat) Identity() interface{}                { return "" }
+func (concat) Combine(a, b interface{}) interface{} { return a.(string) + b.(string) }
+
+func randomValues(n int) ([]int64, []interface{}) {
+	model := make([]int64, n)
+	values := make([]interface{}, n)
+	for i := range model {
+		model[i] = r.Int63n(1000) - 500
+		values[i] = model[i]
+	}
+	return model, values
+}
+
+func aggregate(m segtree.Monoid, model []int64) interface{} {
+	acc := m.Identity()
+	for _, v := range model {
+		acc = m.Combine(acc, v)
+	}
+	return acc
+}
+
+func TestSegTreeRandom(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		monoid segtree.Monoid
+		scale  bool
+	}{
+		{"Sum", segtree.Sum{}, true},
+		{"Min", segtree.Min{}, false},
+		{"Max", segtree.Max{}, false},
+	} {
+		for _, n := range []int{1, 2, 5, 33, 100} {
+			model, values := randomValues(n)
+			st := segtree.NewSegTree(values, c.monoid, segtree.WithAction(segtree.Add{Scale: c.scale}))
+			assign := segtree.NewSegTree(values, c.monoid, segtree.WithAction(segtree.Assign{Scale: c.scale}))
+			assigned := append([]int64(nil), model...)
+
+			for k := 0; k < 300; k++ {
+				lo := r.Intn(n + 1)
+				hi := lo + r.Intn(n+1-lo)
+				v := r.Int63n(100) - 50
+				switch r.Intn(4) {
+				case 0:
+					st.Update(lo, hi, v)
+					assign.Update(lo, hi, v)
+					for i := lo; i < hi; i++ {
+						model[i] += v
+						assigned[i] = v
+					}
+				case 1:
+					if lo < n {
+						st.Set(lo, v)
+						model[lo] = v
+					}
+				default:
+					if got, want := st.Query(lo, hi), aggregate(c.monoid, model[lo:hi]); got != want {
+						t.Fatalf("%v: Query(%v, %v): %v != %v", c.name, lo, hi, got, want)
+					}
+					if got, want := assign.Query(lo, hi), aggregate(c.monoid, assigned[lo:hi]); got != want {
+						t.Fatalf("%v: Query(%v, %v): %v != %v", c.name, lo, hi, got, want)
+					}
+				}
+			}
+
+			for i, v := range st.Values() {
+				if v != model[i] || st.Get(i) != model[i] {
+					t.Errorf("%v: %v != %v", c.name, v, model[i])
+				}
+			}
+		}
+	}
+}
+
+func TestSegTreeNonCommutative(t *testing.T) {
+	st := segtree.NewSegTree([]interface{}{"a", "b", "c", "d", "e"}, concat{})
+	if got := st.Query(1, 4); got != "bcd" {
+		t.Errorf("%v != bcd", got)
+	}
+	st.Set(2, "x")
+	if got := st.Query(0, 5); got != "abxde" {
+		t.Errorf("%v != abxde", got)
+	}
+	if got := st.Query(3, 3); got != "" {
+		t.Errorf("%v != \"\"", got)
+	}
+	if err := st.Update(0, 1, "y"); err != container.ErrNotAugmented {
+		t.Errorf("%v != %v", err, container.ErrNotAugmented)
+	}
+}
+
+func TestSegTreeMaxRight(t *testing.T) {
+	values := []interface{}{int64(3), int64(1), int64(4), int64(1), int64(5), int64(9), int64(2)}
+	st := segtree.NewSegTree(values, segtree.Sum{}, segtree.WithAction(segtree.Add{Scale: true}))
+	st.Update(2, 4, int64(1)) // 3 1 5 2 5 9 2
+
+	for _, c := range []struct {
+		lo    int
+		limit int64
+		want  int
+	}{
+		{0, 0, 0}, {0, 3, 1}, {0, 9, 3}, {0, 100, 7}, {2, 12, 5}, {2, 11, 4}, {5, 8, 5}, {6, 2, 7}, {7, 0, 7},
+	} {
+		got := st.MaxRight(c.lo, func(agg interface{}) bool { return agg.(int64) <= c.limit })
+		if got != c.want {
+			t.Errorf("MaxRight(%v, <= %v): %v != %v", c.lo, c.limit, got, c.want)
+		}
+	}
+}
+
+func TestSegTreeOutOfRange(t *testing.T) {
+	st := segtree.NewSegTree(nil, segtree.Sum{})
+	if st.Len() != 0 || len(st.Values()) != 0 || st.Query(0, 0) != int64(0) {
+		t.Errorf("empty tree is not empty")
+	}
+
+	st = segtree.NewSegTree([]interface{}{int64(1)}, segtree.Sum{})
+	for name, fn := range map[string]func(){
+		"Query": func() { st.Query(0, 2) },
+		"Get":   func() { st.Get(1) },
+		"Set":   func() { st.Set(-1, int64(0)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v didn't panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,337 @@
+// Package skiplist implements a concurrent ordered map as a lazy skip list: searches and range
+// scans never lock, while Insert and Delete only lock the few nodes around the data they change,
+// so that many goroutines can read and write the list at the same time.
+package skiplist
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NzKSO/container"
+)
+
+// MaxLevel is the number of levels of the list, which keeps searches in O(log n) for up to
+// about 4^MaxLevel data.
+const MaxLevel = 32
+
+type node struct {
+	mu          sync.Mutex
+	data        container.Interface // nil for the head
+	next        []atomic.Value      // of *node, one for every level of the node
+	marked      int32               // set once the node is being deleted
+	fullyLinked int32               // set once the node is linked on all of its levels
+}
+
+func newNode(data container.Interface, levels int) *node {
+	return &node{data: data, next: make([]atomic.Value, levels)}
+}
+
+func (n *node) nextAt(level int) *node {
+	next, _ := n.next[level].Load().(*node)
+	return next
+}
+
+func (n *node) setNext(level int, next *node) {
+	n.next[level].Store(next)
+}
+
+func (n *node) isMarked() bool {
+	return atomic.LoadInt32(&n.marked) == 1
+}
+
+func (n *node) isFullyLinked() bool {
+	return atomic.LoadInt32(&n.fullyLinked) == 1
+}
+
+// SkipList represents an ordered map keyed by Less and Find of its data the same way as
+// tree.BSTree, which is safe for concurrent use. Less and Find must not depend on anything
+// that changes while the data is in the list.
+type SkipList struct {
+	head   *node
+	size   int64
+	height int32
+
+	rmu sync.Mutex
+	rnd *rand.Rand
+}
+
+// Option configures a SkipList created by NewSkipList.
+type Option func(*SkipList)
+
+// WithSeed makes the list choose the levels of its nodes using a random number generator seeded
+// with seed, so that inserting the same data in the same order from one goroutine always
+// builds the same list.
+func WithSeed(seed int64) Option {
+	return func(sl *SkipList) {
+		sl.rnd = rand.New(rand.NewSource(seed))
+	}
+}
+
+// NewSkipList returns an empty skip list configured by opts.
+func NewSkipList(opts ...Option) *SkipList {
+	sl := &SkipList{head: newNode(nil, MaxLevel)}
+	for _, opt := range opts {
+		opt(sl)
+	}
+	if sl.rnd == nil {
+		sl.rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return sl
+}
+
+// randomLevel returns the index of the top level of a new node, which is at least l with
+// probability 4^-l.
+func (sl *SkipList) randomLevel() int {
+	sl.rmu.Lock()
+	defer sl.rmu.Unlock()
+
+	level := 0
+	for level < MaxLevel-1 && sl.rnd.Intn(4) == 0 {
+		level++
+	}
+	return level
+}
+
+// before reports whether the data of n is ordered before key.
+func before(n *node, key interface{}) bool {
+	return !n.data.Find(key) && n.data.Less(key)
+}
+
+// find fills preds and succs with the last node before key and the node following it on every
+// level, and returns the highest level on which the node found by key was met, or -1.
+func (sl *SkipList) find(key interface{}, preds, succs []*node) int {
+	found := -1
+	pred := sl.head
+	for level := MaxLevel - 1; level >= 0; level-- {
+		curr := pred.nextAt(level)
+		for curr != nil && before(curr, key) {
+			pred, curr = curr, curr.nextAt(level)
+		}
+		if found == -1 && curr != nil && curr.data.Find(key) {
+			found = level
+		}
+		preds[level], succs[level] = pred, curr
+	}
+	return found
+}
+
+// lockPreds locks the distinct nodes of preds up to topLevel, bottom up, and returns the function
+// unlocking them.
+func lockPreds(preds []*node, topLevel int) func() {
+	var locked []*node
+	for level := 0; level <= topLevel; level++ {
+		if level == 0 || preds[level] != preds[level-1] {
+			preds[level].mu.Lock()
+			locked = append(locked, preds[level])
+		}
+	}
+	return func() {
+		for _, n := range locked {
+			n.mu.Unlock()
+		}
+	}
+}
+
+// Insert inserts data to the list. If data already exists, returns ErrDataExists.
+func (sl *SkipList) Insert(data container.Interface) error {
+	topLevel := sl.randomLevel()
+	var preds, succs [MaxLevel]*node
+
+	for {
+		if found := sl.find(data, preds[:], succs[:]); found != -1 {
+			n := succs[found]
+			if !n.isMarked() {
+				// Wait for the concurrent insertion of n to finish, so that Search finds it.
+				for !n.isFullyLinked() {
+					runtime.Gosched()
+				}
+				return container.ErrDataExists
+			}
+			// n is being deleted, try again once it's gone.
+			continue
+		}
+
+		unlock := lockPreds(preds[:], topLevel)
+		valid := true
+		for level := 0; valid && level <= topLevel; level++ {
+			pred, succ := preds[level], succs[level]
+			valid = !pred.isMarked() && (succ == nil || !succ.isMarked()) && pred.nextAt(level) == succ
+		}
+		if !valid {
+			unlock()
+			continue
+		}
+
+		n := newNode(data, topLevel+1)
+		for level := 0; level <= topLevel; level++ {
+			n.setNext(level, succs[level])
+		}
+		for level := 0; level <= topLevel; level++ {
+			preds[level].setNext(level, n)
+		}
+		atomic.StoreInt32(&n.fullyLinked, 1)
+		unlock()
+
+		atomic.AddInt64(&sl.size, 1)
+		for h := atomic.LoadInt32(&sl.height); int32(topLevel+1) > h; h = atomic.LoadInt32(&sl.height) {
+			if atomic.CompareAndSwapInt32(&sl.height, h, int32(topLevel+1)) {
+				break
+			}
+		}
+		return nil
+	}
+}
+
+// Search returns the data found by key. If the list is empty, returns ErrEmptyList. If not found,
+// returns ErrNotExist. It never blocks.
+func (sl *SkipList) Search(key interface{}) (interface{}, error) {
+	if sl.Empty() {
+		return nil, container.ErrEmptyList
+	}
+
+	var preds, succs [MaxLevel]*node
+	found := sl.find(key, preds[:], succs[:])
+	if found == -1 || !succs[found].isFullyLinked() || succs[found].isMarked() {
+		return nil, container.ErrNotExist
+	}
+	return succs[found].data, nil
+}
+
+// Delete deletes the data found by key. If the list is empty, returns ErrEmptyList. If not found,
+// returns ErrNotExist.
+func (sl *SkipList) Delete(key interface{}) error {
+	if sl.Empty() {
+		return container.ErrEmptyList
+	}
+
+	var (
+		preds, succs [MaxLevel]*node
+		victim       *node
+	)
+	for {
+		found := sl.find(key, preds[:], succs[:])
+		if victim == nil {
+			// Only a node linked on all of its levels, and found on its top level, has been
+			// fully inserted.
+			if found == -1 {
+				return container.ErrNotExist
+			}
+			n := succs[found]
+			if !n.isFullyLinked() || len(n.next)-1 != found || n.isMarked() {
+				return container.ErrNotExist
+			}
+
+			n.mu.Lock()
+			if n.isMarked() {
+				n.mu.Unlock()
+				return container.ErrNotExist
+			}
+			atomic.StoreInt32(&n.marked, 1)
+			victim = n
+		}
+
+		topLevel := len(victim.next) - 1
+		unlock := lockPreds(preds[:], topLevel)
+		valid := true
+		for level := 0; valid && level <= topLevel; level++ {
+			valid = !preds[level].isMarked() && preds[level].nextAt(level) == victim
+		}
+		if !valid {
+			unlock()
+			continue
+		}
+
+		for level := topLevel; level >= 0; level-- {
+			preds[level].setNext(level, victim.nextAt(level))
+		}
+		victim.mu.Unlock()
+		unlock()
+
+		atomic.AddInt64(&sl.size, -1)
+		return nil
+	}
+}
+
+// Range calls visit in order on every data whose key lies between lo and hi inclusive, until visit
+// returns false. It never blocks, and sees every data that stays in the list during the whole call,
+// but may or may not see data inserted or deleted meanwhile.
+func (sl *SkipList) Range(lo, hi interface{}, visit func(data interface{}) bool) {
+	var preds, succs [MaxLevel]*node
+	sl.find(lo, preds[:], succs[:])
+
+	for n := succs[0]; n != nil; n = n.nextAt(0) {
+		if !n.data.Find(hi) && !n.data.Less(hi) {
+			return
+		}
+		if n.isFullyLinked() && !n.isMarked() && !visit(n.data) {
+			return
+		}
+	}
+}
+
+// Ascend calls visit in order on every data until visit returns false, with the same guarantees
+// as Range.
+func (sl *SkipList) Ascend(visit func(data interface{}) bool) {
+	for n := sl.head.nextAt(0); n != nil; n = n.nextAt(0) {
+		if n.isFullyLinked() && !n.isMarked() && !visit(n.data) {
+			return
+		}
+	}
+}
+
+// Size returns the number of data in the list.
+func (sl *SkipList) Size() int {
+	return int(atomic.LoadInt64(&sl.size))
+}
+
+// Empty returns true if the list has no data, otherwise false.
+func (sl *SkipList) Empty() bool {
+	return sl.Size() == 0
+}
+
+// Height returns the number of levels used by the tallest node ever inserted, or 0 if none was.
+func (sl *SkipList) Height() int {
+	return int(atomic.LoadInt32(&sl.height))
+}
+
+// Validate checks the invariants of the list: data on every level are ordered, every node on a
+// level is also on all levels below, no deleted node is linked, and size equals the number of
+// nodes. It returns nil if the list is consistent, otherwise an error wrapping ErrCorrupted. It
+// must not run concurrently with Insert or Delete.
+func (sl *SkipList) Validate() error {
+	onLevel := make(map[*node]bool)
+	count := 0
+	for n := sl.head.nextAt(0); n != nil; n = n.nextAt(0) {
+		if n.isMarked() || !n.isFullyLinked() {
+			return fmt.Errorf("%w: %v is linked while not in the list", container.ErrCorrupted, n.data)
+		}
+		if next := n.nextAt(0); next != nil && !before(n, next.data) {
+			return fmt.Errorf("%w: %v is not ordered before %v", container.ErrCorrupted, n.data, next.data)
+		}
+		onLevel[n] = true
+		count++
+	}
+	if count != sl.Size() {
+		return fmt.Errorf("%w: size is %d but list has %d nodes", container.ErrCorrupted, sl.Size(), count)
+	}
+
+	for level := 1; level < MaxLevel; level++ {
+		below := onLevel
+		onLevel = make(map[*node]bool)
+		var prev *node
+		for n := sl.head.nextAt(level); n != nil; n = n.nextAt(level) {
+			if !below[n] {
+				return fmt.Errorf("%w: %v is on level %d but not below", container.ErrCorrupted, n.data, level)
+			}
+			if prev != nil && !before(prev, n.data) {
+				return fmt.Errorf("%w: %v is not ordered before %v on level %d", container.ErrCorrupted, prev.data, n.data, level)
+			}
+			onLevel[n], prev = true, n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package skiplist_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/skiplist"
+	"github.com/NzKSO/container/testdata"
+)
+
+var r = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func idsOf(sl *skiplist.SkipList) []int {
+	var ids []int
+	sl.Ascend(func(data interface{}) bool {
+		ids = append(ids, data.(*testdata.Corp).ID)
+		return true
+	})
+	return ids
+}
+
+func TestSkipListInsertSearchDelete(t *testing.T) {
+	sl := skiplist.NewSkipList()
+	if _, err := sl.Search(0); err != container.ErrEmptyList {
+		t.Errorf("%v != %v", err, container.ErrEmptyList)
+	}
+	if err := sl.Delete(0); err != container.ErrEmptyList {
+		t.Errorf("%v != %v", err, container.ErrEmptyList)
+	}
+
+	ri := r.Perm(len(testdata.TestCases))
+	for _, iv := range ri {
+		if err := sl.Insert(&testdata.TestCases[iv]); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+	if sl.Size() != len(ri) || sl.Empty() {
+		t.Errorf("%v != %v", sl.Size(), len(ri))
+	}
+	for _, iv := range ri {
+		if itf, err := sl.Search(testdata.TestCases[iv].ID); err != nil || itf != &testdata.TestCases[iv] {
+			t.Errorf("(%v != nil) or (%v != %v)", err, itf, &testdata.TestCases[iv])
+		}
+		if err := sl.Insert(&testdata.Corp{ID: testdata.TestCases[iv].ID}); err != container.ErrDataExists {
+			t.Errorf("%v != %v", err, container.ErrDataExists)
+		}
+	}
+	if err := sl.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	for i, iv := range r.Perm(len(ri)) {
+		if err := sl.Delete(testdata.TestCases[iv].ID); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+		if _, err := sl.Search(testdata.TestCases[iv].ID); err == nil {
+			t.Errorf("%v is still in the list", testdata.TestCases[iv])
+		}
+		if sl.Size() != len(ri)-i-1 {
+			t.Errorf("%v != %v", sl.Size(), len(ri)-i-1)
+		}
+	}
+	if !sl.Empty() || idsOf(sl) != nil {
+		t.Errorf("list is not empty")
+	}
+	if err := sl.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestSkipListRange(t *testing.T) {
+	sl := skiplist.NewSkipList()
+	for _, id := range r.Perm(50) {
+		sl.Insert(&testdata.Corp{ID: id * 2})
+	}
+
+	for lo := -2; lo <= 100; lo += 3 {
+		for hi := lo - 1; hi <= 101; hi += 5 {
+			var got []int
+			sl.Range(lo, hi, func(data interface{}) bool {
+				got = append(got, data.(*testdata.Corp).ID)
+				return true
+			})
+			want := 0
+			for id := 0; id < 100; id += 2 {
+				if id >= lo && id <= hi {
+					if want >= len(got) || got[want] != id {
+						t.Fatalf("Range(%v, %v): %v misses %v", lo, hi, got, id)
+					}
+					want++
+				}
+			}
+			if want != len(got) {
+				t.Errorf("Range(%v, %v): %v has %v data", lo, hi, got, want)
+			}
+		}
+	}
+
+	var got []int
+	sl.Range(11, 90, func(data interface{}) bool {
+		got = append(got, data.(*testdata.Corp).ID)
+		return len(got) < 2
+	})
+	if len(got) != 2 || got[0] != 12 || got[1] != 14 {
+		t.Errorf("%v != [12 14]", got)
+	}
+}
+
+func TestSkipListSeed(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		sl1 := skiplist.NewSkipList(skiplist.WithSeed(seed))
+		sl2 := skiplist.NewSkipList(skiplist.WithSeed(seed))
+		for id := 0; id < 1000; id++ {
+			sl1.Insert(&testdata.Corp{ID: id})
+			sl2.Insert(&testdata.Corp{ID: id})
+			if sl1.Height() != sl2.Height() {
+				t.Fatalf("seed %v: %v != %v", seed, sl1.Height(), sl2.Height())
+			}
+		}
+		if h := sl1.Height(); h < 2 || h > 12 {
+			t.Errorf("seed %v: height %v of 1000 nodes", seed, h)
+		}
+	}
+}
+
+func TestSkipListConcurrent(t *testing.T) {
+	const (
+		goroutines = 8
+		keys       = 2000
+		ops        = 5000
+	)
+	sl := skiplist.NewSkipList()
+
+	// Every goroutine owns the keys equal to its number modulo goroutines, and keeps its own
+	// model of them, while also reading and scanning keys owned by the others.
+	models := make([]map[int]bool, goroutines)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		models[g] = make(map[int]bool)
+		wg.Add(1)
+		go func(g int, seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			model := models[g]
+			for i := 0; i < ops; i++ {
+				id := rnd.Intn(keys/goroutines)*goroutines + g
+				switch rnd.Intn(4) {
+				case 0:
+					if err := sl.Delete(id); (err == nil) != model[id] {
+						t.Errorf("Delete(%v): %v", id, err)
+					}
+					delete(model, id)
+				case 1:
+					if err := sl.Insert(&testdata.Corp{ID: id}); (err == nil) == model[id] {
+						t.Errorf("Insert(%v): %v", id, err)
+					}
+					model[id] = true
+				case 2:
+					if _, err := sl.Search(id); (err == nil) != model[id] {
+						t.Errorf("Search(%v): %v", id, err)
+					}
+					sl.Search(rnd.Intn(keys))
+				case 3:
+					prev := -1
+					sl.Range(rnd.Intn(keys), rnd.Intn(keys), func(data interface{}) bool {
+						if id := data.(*testdata.Corp).ID; id <= prev {
+							t.Errorf("%v after %v", id, prev)
+						} else {
+							prev = id
+						}
+						return true
+					})
+				}
+			}
+		}(g, r.Int63())
+	}
+	wg.Wait()
+
+	if err := sl.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	want := 0
+	for _, model := range models {
+		want += len(model)
+	}
+	if sl.Size() != want || len(idsOf(sl)) != want {
+		t.Errorf("%v != %v", sl.Size(), want)
+	}
+	for g, model := range models {
+		for id := range model {
+			if _, err := sl.Search(id); err != nil {
+				t.Errorf("goroutine %v: Search(%v): %v", g, id, err)
+			}
+		}
+	}
+}
+
+func TestSkipListConcurrentContention(t *testing.T) {
+	const goroutines = 8
+	sl := skiplist.NewSkipList()
+
+	// All goroutines fight over the same few keys, each key must be inserted as often as it is
+	// deleted, give or take the last insertion.
+	inserted := make([]int64, 16)
+	deleted := make([]int64, 16)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			for i := 0; i < 5000; i++ {
+				id := rnd.Intn(len(inserted))
+				if rnd.Intn(2) == 0 {
+					if sl.Insert(&testdata.Corp{ID: id}) == nil {
+						mu.Lock()
+						inserted[id]++
+						mu.Unlock()
+					}
+				} else if sl.Delete(id) == nil {
+					mu.Lock()
+					deleted[id]++
+					mu.Unlock()
+				}
+			}
+		}(r.Int63())
+	}
+	wg.Wait()
+
+	if err := sl.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	for id := range inserted {
+		_, err := sl.Search(id)
+		present := int64(0)
+		if err == nil {
+			present = 1
+		}
+		if inserted[id]-deleted[id] != present {
+			t.Errorf("%v: inserted %v times, deleted %v times, present %v", id, inserted[id], deleted[id], present)
+		}
+	}
+}
@@ -57,3 +57,13 @@ func (ls *LStack) Reset() {
 func (ls *LStack) Empty() bool {
 	return ls.size == 0
 }
+
+// Clone returns a copy of the LinkedStack, which shares the data but not the nodes with ls.
+func (ls *LStack) Clone() *LStack {
+	clone := &LStack{size: ls.size}
+	for link, n := &clone.head, ls.head; n != nil; n = n.next {
+		*link = &node{data: n.data}
+		link = &(*link).next
+	}
+	return clone
+}
@@ -55,3 +55,26 @@ func TestLStackReset(t *testing.T) {
 		t.Errorf("LStack is empty? %v", ls.Empty())
 	}
 }
+
+func TestLStackClone(t *testing.T) {
+	ls := createLStack()
+	c := ls.Clone()
+
+	ls.Pop()
+	ls.Push("pushed")
+	if c.Size() != len(testdata.TestCases) {
+		t.Errorf("%v != %v", c.Size(), len(testdata.TestCases))
+	}
+	for i := len(testdata.TestCases) - 1; i >= 0; i-- {
+		v := c.Pop()
+		if testdata.TestCases[i] != v {
+			t.Errorf("%v != %v", testdata.TestCases[i], v)
+		}
+	}
+	if v := ls.Pop(); v != "pushed" {
+		t.Errorf("%v != pushed", v)
+	}
+	if !stack.NewLStack().Clone().Empty() {
+		t.Errorf("clone of empty stack is not empty")
+	}
+}
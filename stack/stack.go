@@ -46,3 +46,11 @@ func (s *Stack) Reset() {
 func (s *Stack) Empty() bool {
 	return len(s.data) == 0
 }
+
+// Clone returns a copy of the Stack, which shares the data but not the underlying array with s.
+func (s *Stack) Clone() *Stack {
+	if s.data == nil {
+		return &Stack{}
+	}
+	return &Stack{append([]interface{}(nil), s.data...)}
+}
@@ -55,3 +55,27 @@ func TestStackReset(t *testing.T) {
 		t.Errorf("Stack is empty? %v", s.Empty())
 	}
 }
+
+func TestStackClone(t *testing.T) {
+	s := createStack()
+	c := s.Clone()
+
+	// Overwriting the top of s must not show through c.
+	s.Pop()
+	s.Push("pushed")
+	if c.Size() != len(testdata.TestCases) {
+		t.Errorf("%v != %v", c.Size(), len(testdata.TestCases))
+	}
+	for i := len(testdata.TestCases) - 1; i >= 0; i-- {
+		v := c.Pop()
+		if testdata.TestCases[i] != v {
+			t.Errorf("%v != %v", testdata.TestCases[i], v)
+		}
+	}
+	if v := s.Pop(); v != "pushed" {
+		t.Errorf("%v != pushed", v)
+	}
+	if !stack.NewStack().Clone().Empty() {
+		t.Errorf("clone of empty stack is not empty")
+	}
+}
@@ -54,6 +54,35 @@ func (c *Corp) Find(key interface{}) bool {
 	return false
 }
 
+// IDSetter is a Corp whose Set changes its ID, that is its key, used for testing how containers
+// handle a key changed by Setter.Set
+type IDSetter struct {
+	Corp
+}
+
+// Set implements interface Setter in package container, setting the ID to i
+func (is *IDSetter) Set(i interface{}) {
+	is.ID = i.(int)
+}
+
+// Less implements interface Lesser in package container, it accepts an *IDSetter besides the
+// keys accepted by Corp
+func (is *IDSetter) Less(kv interface{}) bool {
+	if v, ok := kv.(*IDSetter); ok {
+		return is.ID <= v.ID
+	}
+	return is.Corp.Less(kv)
+}
+
+// Find implements interface Finder in package container, it accepts an *IDSetter besides the
+// keys accepted by Corp
+func (is *IDSetter) Find(key interface{}) bool {
+	if v, ok := key.(*IDSetter); ok {
+		return is.ID == v.ID
+	}
+	return is.Corp.Find(key)
+}
+
 // TestCases contain some test data loaded from testdata.json, which use lately by package to test
 var TestCases []Corp
 
@@ -0,0 +1,190 @@
+package tree
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/stack"
+)
+
+// Monoid describes a value kept for every subtree of a tree, such as the sum of some field of
+// its data. The value of a subtree is Combine(Combine(left, FromValue(data)), right), where left
+// and right are the values of the child subtrees, or Identity for missing children. Combine must
+// be associative and Identity must be its identity element, but Combine needn't be commutative.
+type Monoid interface {
+	Identity() interface{}
+	Combine(a, b interface{}) interface{}
+	FromValue(data interface{}) interface{}
+}
+
+// WithMonoid makes the tree maintain the value described by m for every subtree, which enables
+// Aggregate. Every insertion and deletion then calls Combine O(height) times.
+func WithMonoid(m Monoid) Option {
+	return func(bt *BSTree) {
+		bt.monoid = m
+	}
+}
+
+func countOf(tn *Tnode) int {
+	if tn == nil {
+		return 0
+	}
+	return tn.count
+}
+
+func (bt *BSTree) aggOf(tn *Tnode) interface{} {
+	if tn == nil {
+		return bt.monoid.Identity()
+	}
+	return tn.agg
+}
+
+// summarize computes the count and the aggregate of tn from its children.
+func (bt *BSTree) summarize(tn *Tnode) (int, interface{}) {
+	count := 1 + countOf(tn.lightChild) + countOf(tn.rightChild)
+	if bt.monoid == nil {
+		return count, nil
+	}
+
+	m := bt.monoid
+	return count, m.Combine(m.Combine(bt.aggOf(tn.lightChild), m.FromValue(tn.data)), bt.aggOf(tn.rightChild))
+}
+
+// refresh recomputes the count and the aggregate of tn from its children.
+func (bt *BSTree) refresh(tn *Tnode) {
+	tn.count, tn.agg = bt.summarize(tn)
+}
+
+// fixUp refreshes tn and all of its ancestors, it must be called on the deepest node changed by
+// any modification of the tree.
+func (bt *BSTree) fixUp(tn *Tnode) {
+	for ; tn != nil; tn = tn.parent {
+		bt.refresh(tn)
+	}
+}
+
+// fixAll refreshes every node of the tree rooted at tn, children before their parents.
+func (bt *BSTree) fixAll(tn *Tnode) {
+	if tn == nil {
+		return
+	}
+
+	// Popping nodes in reverse preorder visits every child before its parent.
+	preorder, ls := stack.NewLStack(), stack.NewLStack()
+	ls.Push(tn)
+	for !ls.Empty() {
+		tn = ls.Pop().(*Tnode)
+		preorder.Push(tn)
+		if tn.lightChild != nil {
+			ls.Push(tn.lightChild)
+		}
+		if tn.rightChild != nil {
+			ls.Push(tn.rightChild)
+		}
+	}
+	for !preorder.Empty() {
+		bt.refresh(preorder.Pop().(*Tnode))
+	}
+}
+
+// checkAugment reports whether the count and the aggregate of tn are consistent with its children.
+func (bt *BSTree) checkAugment(tn *Tnode) error {
+	count, agg := bt.summarize(tn)
+	if tn.count != count {
+		return fmt.Errorf("%w: subtree of %v has %d nodes but counts %d", container.ErrCorrupted, tn.data, count, tn.count)
+	}
+	if !reflect.DeepEqual(tn.agg, agg) {
+		return fmt.Errorf("%w: subtree of %v aggregates to %v but holds %v", container.ErrCorrupted, tn.data, agg, tn.agg)
+	}
+	return nil
+}
+
+// atOrAfter reports whether v is found by key or ordered after it.
+func (bt *BSTree) atOrAfter(v container.Interface, key interface{}) bool {
+	bt.observe(v, key)
+	return v.Find(key) || !bt.goesRight(v, key)
+}
+
+// atOrBefore reports whether v is found by key or ordered before it.
+func (bt *BSTree) atOrBefore(v container.Interface, key interface{}) bool {
+	bt.observe(v, key)
+	return v.Find(key) || bt.goesRight(v, key)
+}
+
+// Aggregate combines the values of the Monoid of the tree for every data whose key lies between
+// lo and hi inclusive, in order, which takes O(height) calls to Combine. It returns Identity if no
+// such data exist, and ErrNotAugmented if the tree was created without WithMonoid.
+func (bt *BSTree) Aggregate(lo, hi interface{}) (interface{}, error) {
+	if bt.monoid == nil {
+		return nil, container.ErrNotAugmented
+	}
+
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	// split is the first node on the way down that lies in range, the paths to lo and hi part there.
+	split := bt.root
+	for split != nil {
+		v := split.data.(container.Interface)
+		if !bt.atOrAfter(v, lo) {
+			split = split.rightChild
+		} else if !bt.atOrBefore(v, hi) {
+			split = split.lightChild
+		} else {
+			break
+		}
+	}
+
+	m := bt.monoid
+	if split == nil {
+		return m.Identity(), nil
+	}
+
+	// Every node at or after lo on the way down to lo lies in range together with its right subtree,
+	// and every node at or before hi on the way down to hi with its left subtree.
+	left := m.Identity()
+	for tn := split.lightChild; tn != nil; {
+		if bt.atOrAfter(tn.data.(container.Interface), lo) {
+			left = m.Combine(m.Combine(m.FromValue(tn.data), bt.aggOf(tn.rightChild)), left)
+			tn = tn.lightChild
+		} else {
+			tn = tn.rightChild
+		}
+	}
+	right := m.Identity()
+	for tn := split.rightChild; tn != nil; {
+		if bt.atOrBefore(tn.data.(container.Interface), hi) {
+			right = m.Combine(right, m.Combine(bt.aggOf(tn.lightChild), m.FromValue(tn.data)))
+			tn = tn.rightChild
+		} else {
+			tn = tn.lightChild
+		}
+	}
+
+	return m.Combine(m.Combine(left, m.FromValue(split.data)), right), nil
+}
+
+// Rank returns the number of data in the tree ordered before key, which is the index key has or
+// would have in InorderTrav. It takes O(height) whether or not the tree has a Monoid.
+func (bt *BSTree) Rank(key interface{}) int {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	var rank int
+	for tn := bt.root; tn != nil; {
+		v := tn.data.(container.Interface)
+		bt.observe(v, key)
+		if v.Find(key) {
+			return rank + countOf(tn.lightChild)
+		}
+
+		if bt.goesRight(v, key) {
+			rank += countOf(tn.lightChild) + 1
+			tn = tn.rightChild
+		} else {
+			tn = tn.lightChild
+		}
+	}
+	return rank
+}
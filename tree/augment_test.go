@@ -0,0 +1,150 @@
+package tree_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+// idSum sums the IDs of the data.
+type idSum struct{}
+
+func (idSum) Identity() interface{}                { return 0 }
+func (idSum) Combine(a, b interface{}) interface{} { return a.(int) + b.(int) }
+func (idSum) FromValue(data interface{}) interface{} {
+	return data.(*testdata.Corp).ID
+}
+
+// nameConcat joins the names of the data in order, Combine isn't commutative.
+type nameConcat struct{}
+
+func (nameConcat) Identity() interface{}                { return "" }
+func (nameConcat) Combine(a, b interface{}) interface{} { return a.(string) + b.(string) }
+func (nameConcat) FromValue(data interface{}) interface{} {
+	return data.(*testdata.Corp).Name + ","
+}
+
+// namesBetween joins the names of testCase with IDs between lo and hi the way nameConcat does.
+func namesBetween(lo, hi int) string {
+	byID := make(map[int]string)
+	for _, c := range testCase {
+		byID[c.ID] = c.Name
+	}
+
+	var sb strings.Builder
+	for id := lo; id <= hi; id++ {
+		if name, ok := byID[id]; ok {
+			sb.WriteString(name + ",")
+		}
+	}
+	return sb.String()
+}
+
+func TestBSTreeAggregate(t *testing.T) {
+	bt := tree.NewBSTree(tree.WithMonoid(nameConcat{}))
+	for _, iv := range r.Perm(len(testCase)) {
+		bt.Insert(&testCase[iv])
+	}
+
+	for lo := -1; lo <= len(testCase); lo++ {
+		for hi := lo - 1; hi <= len(testCase); hi++ {
+			if agg, err := bt.Aggregate(lo, hi); err != nil || agg != namesBetween(lo, hi) {
+				t.Errorf("Aggregate(%v, %v): (%v != nil) or (%v != %v)", lo, hi, err, agg, namesBetween(lo, hi))
+			}
+		}
+	}
+
+	// Update refreshes the aggregates on the way to the root.
+	saved := testCase[7].Name
+	defer func() { testCase[7].Name = saved }()
+	bt.Update(4, "Pear")
+	if agg, _ := bt.Aggregate(3, 5); agg != "Google,Pear,Netflix," {
+		t.Errorf("%v != Google,Pear,Netflix,", agg)
+	}
+
+	bt.Invert()
+	if agg, _ := bt.Aggregate(5, 3); agg != "Netflix,Pear,Google," {
+		t.Errorf("%v != Netflix,Pear,Google,", agg)
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	if _, err := tree.NewBSTree().Aggregate(0, 10); err != container.ErrNotAugmented {
+		t.Errorf("%v != %v", err, container.ErrNotAugmented)
+	}
+	if agg, err := tree.NewBSTree(tree.WithMonoid(idSum{})).Aggregate(0, 10); err != nil || agg != 0 {
+		t.Errorf("(%v != nil) or (%v != 0)", err, agg)
+	}
+}
+
+func TestBSTreeAggregateModified(t *testing.T) {
+	bt := tree.NewBSTree(tree.WithMonoid(idSum{}))
+	model := make(map[int]bool)
+	for i := 0; i < 2000; i++ {
+		id := r.Intn(200)
+		if r.Intn(3) == 0 {
+			bt.Delete(id)
+			delete(model, id)
+		} else {
+			bt.Insert(&testdata.Corp{ID: id})
+			model[id] = true
+		}
+
+		lo, hi := r.Intn(220)-10, r.Intn(220)-10
+		var sum, rank int
+		for id := range model {
+			if id >= lo && id <= hi {
+				sum += id
+			}
+			if id < lo {
+				rank++
+			}
+		}
+		if agg, _ := bt.Aggregate(lo, hi); agg != sum {
+			t.Fatalf("Aggregate(%v, %v): %v != %v", lo, hi, agg, sum)
+		}
+		if got := bt.Rank(lo); got != rank {
+			t.Fatalf("Rank(%v): %v != %v", lo, got, rank)
+		}
+	}
+
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	for _, clone := range []*tree.BSTree{bt.Clone(nil), bt.Mirror()} {
+		if err := clone.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+}
+
+func TestBSTreeRank(t *testing.T) {
+	bt, _ := createTree(r.Perm(len(testCase)))
+	for id := -1; id <= len(testCase); id++ {
+		want := id
+		if want < 0 {
+			want = 0
+		}
+		if got := bt.Rank(id); got != want {
+			t.Errorf("Rank(%v): %v != %v", id, got, want)
+		}
+	}
+
+	for _, iv := range []int{2, 5, 7} {
+		bt.Delete(testCase[iv].ID)
+	}
+	// 7, 2 and 4 are gone.
+	if got := bt.Rank(8); got != 5 {
+		t.Errorf("%v != 5", got)
+	}
+	if got := bt.Rank(4); got != 3 {
+		t.Errorf("%v != 3", got)
+	}
+	if got := tree.NewBSTree().Rank(3); got != 0 {
+		t.Errorf("%v != 0", got)
+	}
+}
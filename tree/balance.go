@@ -0,0 +1,54 @@
+package tree
+
+// balancer keeps a tree balanced by rotating its nodes, its methods are called with the tree
+// locked for writing.
+type balancer interface {
+	// linked is called once tn was linked into bt as a leaf.
+	linked(bt *BSTree, tn *Tnode)
+
+	// unlinking is called before tn is unlinked from bt.
+	unlinking(bt *BSTree, tn *Tnode)
+
+	// unlinked is called after a node was unlinked from bt, with the deepest node whose subtree
+	// lost it, or nil if that node was the root.
+	unlinked(bt *BSTree, changed *Tnode)
+
+	// check returns an error if the balancing invariant doesn't hold at tn.
+	check(tn *Tnode) error
+
+	// fork returns a balancer for a copy of the tree.
+	fork() balancer
+}
+
+// rotateUp rotates tn above its parent, which becomes its child. The inorder of the tree
+// stays the same, as do the counts and aggregates of all but these two nodes.
+func (bt *BSTree) rotateUp(tn *Tnode) {
+	parent, grand := tn.parent, tn.parent.parent
+	if parent.lightChild == tn {
+		parent.lightChild = tn.rightChild
+		if tn.rightChild != nil {
+			tn.rightChild.parent = parent
+		}
+		tn.rightChild = parent
+	} else {
+		parent.rightChild = tn.lightChild
+		if tn.lightChild != nil {
+			tn.lightChild.parent = parent
+		}
+		tn.lightChild = parent
+	}
+	parent.parent = tn
+
+	tn.parent = grand
+	if grand == nil {
+		bt.root = tn
+	} else if grand.lightChild == parent {
+		grand.lightChild = tn
+	} else {
+		grand.rightChild = tn
+	}
+
+	bt.refresh(parent)
+	bt.refresh(tn)
+	bt.mods++
+}
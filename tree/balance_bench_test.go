@@ -0,0 +1,99 @@
+package tree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+const benchSize = 1 << 16
+
+// searcher is implemented by BSTree, Treap and SplayTree.
+type searcher interface {
+	Insert(data container.Interface) error
+	Search(key interface{}) (interface{}, error)
+}
+
+var benchTrees = []struct {
+	name   string
+	create func() searcher
+}{
+	{"BSTree", func() searcher { return tree.NewBSTree() }},
+	{"Treap", func() searcher { return tree.NewTreap(tree.WithSeed(1)) }},
+	{"SplayTree", func() searcher { return tree.NewSplayTree() }},
+}
+
+// workload returns n keys in [0, benchSize), uniformly distributed if s is 0, or otherwise
+// Zipf distributed with parameter s over a random permutation of the IDs, so the hot keys
+// are scattered over the tree rather than clustered at one end.
+func workload(n int, s float64) []int {
+	rnd := rand.New(rand.NewSource(1))
+	keys := make([]int, n)
+	if s == 0 {
+		for i := range keys {
+			keys[i] = rnd.Intn(benchSize)
+		}
+		return keys
+	}
+
+	perm := rnd.Perm(benchSize)
+	z := rand.NewZipf(rnd, s, 1, benchSize-1)
+	for i := range keys {
+		keys[i] = perm[z.Uint64()]
+	}
+	return keys
+}
+
+func benchmarkWorkload(b *testing.B, s float64) {
+	data := make([]testdata.Corp, benchSize)
+	for i, id := range rand.New(rand.NewSource(2)).Perm(benchSize) {
+		data[i].ID = id
+	}
+	keys := workload(benchSize, s)
+
+	for _, bt := range benchTrees {
+		t := bt.create()
+		for i := range data {
+			t.Insert(&data[i])
+		}
+
+		b.Run(bt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				t.Search(keys[n%benchSize])
+			}
+		})
+	}
+}
+
+func BenchmarkSearchUniform(b *testing.B) {
+	benchmarkWorkload(b, 0)
+}
+
+func BenchmarkSearchSkewed(b *testing.B) {
+	benchmarkWorkload(b, 1.2)
+}
+
+func BenchmarkInsertSorted(b *testing.B) {
+	// A plain BSTree degenerates into a chain on sorted input, keep n small.
+	const n = 1 << 12
+	data := make([]testdata.Corp, n)
+	for i := range data {
+		data[i].ID = i
+	}
+
+	for _, bt := range benchTrees {
+		b.Run(bt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for k := 0; k < b.N; k++ {
+				t := bt.create()
+				for i := range data {
+					t.Insert(&data[i])
+				}
+			}
+		})
+	}
+}
@@ -2,18 +2,46 @@
 package tree
 
 import (
+	"fmt"
 	"math"
 	"reflect"
+	"runtime/debug"
+	"sync"
 
 	"github.com/NzKSO/container"
 	"github.com/NzKSO/container/queue"
+	"github.com/NzKSO/container/stack"
 )
 
-// Tnode represents a node in a binary tree.
+// Tree is implemented by the search trees of the library, BSTree and the balanced trees built
+// on it in this package, as well as btree.BTree, so that they can be used interchangeably.
+type Tree interface {
+	Insert(data container.Interface) error
+	Search(key interface{}) (interface{}, error)
+	Delete(key interface{}) error
+	Update(key interface{}, val interface{}) error
+	Traversal(TravType TraversalType) <-chan interface{}
+	Size() int
+	Empty() bool
+	Reset()
+}
+
+var (
+	_ Tree = (*BSTree)(nil)
+	_ Tree = (*Treap)(nil)
+	_ Tree = (*SplayTree)(nil)
+)
+
+// Tnode represents a node in a binary tree. Besides its data, it keeps the number of nodes of
+// the subtree rooted at it and, if the tree has a Monoid, the aggregate of that subtree.
 type Tnode struct {
 	lightChild *Tnode
 	rightChild *Tnode
+	parent     *Tnode
 	data       interface{}
+	count      int
+	agg        interface{}
+	priority   uint32 // only used by Treap
 }
 
 // GetLchild returns member lightChild pointed by tn.
@@ -26,100 +54,190 @@ func (tn *Tnode) GetRchild() *Tnode {
 	return tn.rightChild
 }
 
+// GetParent returns member parent pointed by tn, which is nil for the root.
+func (tn *Tnode) GetParent() *Tnode {
+	return tn.parent
+}
+
 // GetData returns member data pointed by tn.
 func (tn *Tnode) GetData() interface{} {
 	return tn.data
 }
 
-// BSTree represents an binary tree.
+// BSTree represents an binary tree. Every structural modification of the tree is counted,
+// so that traversals and cursors can detect it.
 type BSTree struct {
-	root *Tnode
-	size int
+	rw       sync.RWMutex
+	root     *Tnode
+	size     int
+	mods     uint64
+	inverted bool
+	monoid   Monoid
+	balancer balancer
+	seed     *int64
+	checker  *container.Checker
+}
+
+// Option configures a BSTree created by NewBSTree.
+type Option func(*BSTree)
+
+// WithChecker makes the tree report every comparison between stored values to c, which is
+// meant for debugging Less and Find implementations. Comparisons with keys that don't implement
+// container.Interface, such as plain search keys, can't be checked and are reported as violations
+// of container.RuleKeyType.
+func WithChecker(c *container.Checker) Option {
+	return func(bt *BSTree) {
+		bt.checker = c
+	}
 }
 
 // TraversalType represents type of traversal in binary tree
 type TraversalType int
 
 // These constants respectively denotes traversing in Inorder, Preorder,
-// Postorder, levelorder.
+// Postorder, levelorder, and Inorder using Morris traversal.
+//
+// MorrisInorderTrav yields the same sequence as InorderTrav using O(1) extra space, walking the
+// tree without a stack by temporarily threading right children back to their successors. No one
+// may see the threads, so the tree stays locked for writing until the channel is drained: other
+// calls on the tree wait until then, which means the receiver must not call the tree itself and
+// must drain the channel.
 const (
 	InorderTrav TraversalType = iota
 	PreorderTrav
 	PostorderTrav
 	LevelTrav
+	MorrisInorderTrav
 )
 
 // TravFunc is used for traversing the binary search tree, which accepts a pointer to root node
 // and a channel as used to send traversing sequence.
 type TravFunc func(root *Tnode, ch chan<- interface{})
 
-// NewBSTree returns an empty binary tree.
-func NewBSTree() *BSTree {
-	return &BSTree{}
+// NewBSTree returns an empty binary tree configured by opts.
+func NewBSTree(opts ...Option) *BSTree {
+	bt := &BSTree{}
+	for _, opt := range opts {
+		opt(bt)
+	}
+	return bt
 }
 
-func insert(tn *Tnode, data container.Interface) (*Tnode, error) {
-	if tn == nil {
-		return &Tnode{data: data}, nil
+// observe reports the comparison of itf with key to the checker of bt, if any. Keys that can't
+// be checked are recorded by the checker as violations, so the error of Observe adds nothing.
+func (bt *BSTree) observe(itf container.Interface, key interface{}) {
+	if bt.checker != nil {
+		bt.checker.Observe(itf, key)
 	}
+}
 
-	itf := tn.data.(container.Interface)
-	if itf.Find(data) {
-		return tn, container.ErrDataExists
-	}
+// goesRight reports whether key belongs to the right of v, which is the case if Less returns true,
+// or false once the tree has been inverted. Callers must rule out that v is found by key first.
+func (bt *BSTree) goesRight(v container.Lesser, key interface{}) bool {
+	return v.Less(key) != bt.inverted
+}
 
-	var err error
-	toRight := itf.Less(data)
-	if toRight {
-		tn.rightChild, err = insert(tn.rightChild, data)
-	} else {
-		tn.lightChild, err = insert(tn.lightChild, data)
+func (bt *BSTree) insert(data container.Interface) error {
+	var parent *Tnode
+	link := &bt.root
+	for *link != nil {
+		itf := (*link).data.(container.Interface)
+		bt.observe(itf, data)
+		if itf.Find(data) {
+			return container.ErrDataExists
+		}
+
+		parent = *link
+		toRight := bt.goesRight(itf, data)
+		if toRight {
+			link = &(*link).rightChild
+		} else {
+			link = &(*link).lightChild
+		}
 	}
 
-	return tn, err
+	*link = &Tnode{parent: parent, data: data}
+	bt.fixUp(*link)
+	if bt.balancer != nil {
+		bt.balancer.linked(bt, *link)
+	}
+	return nil
 }
 
 // Insert inserts data to binary tree.
 func (bt *BSTree) Insert(data container.Interface) error {
-	var err error
-	bt.root, err = insert(bt.root, data)
-	if err != nil {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if err := bt.insert(data); err != nil {
 		return err
 	}
 	bt.size++
+	bt.mods++
 
 	return nil
 }
 
-func lookup(find *Tnode, parent *Tnode, key interface{}) (*Tnode, *Tnode) {
-	if find == nil {
-		return nil, nil
-	}
+// lookup returns the node found by key, or nil if not found.
+func (bt *BSTree) lookup(key interface{}) *Tnode {
+	find, _ := bt.descend(key)
+	return find
+}
 
-	v := find.data.(container.Interface)
-	if v.Find(key) {
-		return find, parent
-	}
+// descend walks down from the root towards key, it returns the node found by key, or nil if
+// not found, and the last node visited, which is nil only if the tree is empty.
+func (bt *BSTree) descend(key interface{}) (*Tnode, *Tnode) {
+	var last *Tnode
+	for walk := bt.root; walk != nil; {
+		last = walk
+		v := walk.data.(container.Interface)
+		bt.observe(v, key)
+		if v.Find(key) {
+			return walk, last
+		}
 
-	toRight := v.Less(key)
-	if toRight {
-		find, parent = lookup(find.rightChild, find, key)
-	} else {
-		find, parent = lookup(find.lightChild, find, key)
+		if bt.goesRight(v, key) {
+			walk = walk.rightChild
+		} else {
+			walk = walk.lightChild
+		}
 	}
 
-	return find, parent
+	return nil, last
+}
+
+// lowerBound returns the node found by key, or if there is none, the node of the smallest
+// data that Less orders after key, or nil if no such node exists.
+func (bt *BSTree) lowerBound(key interface{}) *Tnode {
+	var bound *Tnode
+	for walk := bt.root; walk != nil; {
+		v := walk.data.(container.Interface)
+		bt.observe(v, key)
+		if v.Find(key) {
+			return walk
+		}
 
+		if bt.goesRight(v, key) {
+			walk = walk.rightChild
+		} else {
+			bound = walk
+			walk = walk.lightChild
+		}
+	}
+	return bound
 }
 
 // Search search tree to found data associated with the provided key. If the tree is empty, returns ErrEmptyTree.
 // If not found, returns ErrNotExist.
 func (bt *BSTree) Search(key interface{}) (interface{}, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
 	if bt.root == nil && bt.size == 0 {
 		return nil, container.ErrEmptyTree
 	}
 
-	tn, _ := lookup(bt.root, nil, key)
+	tn := bt.lookup(key)
 	if tn == nil {
 		return nil, container.ErrNotExist
 	}
@@ -130,130 +248,251 @@ func (bt *BSTree) Search(key interface{}) (interface{}, error) {
 // Delete deletes the data found by key. if tree is empty, Delete returns ErrEmptyTree,
 // if the data doesn't exist, it will return ErrNotExist.
 func (bt *BSTree) Delete(key interface{}) error {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
 	if bt.root == nil && bt.size == 0 {
 		return container.ErrEmptyTree
 	}
 
-	find, parent := lookup(bt.root, nil, key)
+	find := bt.lookup(key)
 	if find == nil {
 		return container.ErrNotExist
 	}
 
-	if find.lightChild == nil && find.rightChild == nil { // Node to be removed has 0 child node
-		if parent == nil {
-			bt.root = nil
-		} else {
-			if parent.lightChild == find {
-				parent.lightChild = nil
-			} else {
-				parent.rightChild = nil
-			}
-		}
-	} else if find.lightChild != nil && find.rightChild == nil { // Node to be removed has 1 left child node
-		if parent == nil {
-			bt.root = find.lightChild
-		} else {
-			if parent.lightChild == find {
-				parent.lightChild = find.lightChild
-			} else {
-				parent.rightChild = find.lightChild
-			}
-		}
-	} else if find.lightChild == nil && find.rightChild != nil { // Node to be removed has 1 right child node
-		if parent == nil {
-			bt.root = find.rightChild
-		} else {
-			if parent.lightChild == find {
-				parent.lightChild = find.rightChild
-			} else {
-				parent.rightChild = find.rightChild
-			}
-		}
-	} else if find.lightChild != nil && find.rightChild != nil { // Node to be removed has 2 child node
-		leftMost, leftMostParent := findLeftMostNode(find.rightChild, find)
-		find.data = leftMost.data
-		if leftMostParent == find {
-			if leftMost.rightChild != nil {
-				leftMostParent.rightChild = leftMost.rightChild
-			} else {
-				leftMostParent.rightChild = nil
-			}
-		} else {
-			if leftMost.rightChild != nil {
-				leftMostParent.lightChild = leftMost.rightChild
-			} else {
-				leftMostParent.lightChild = nil
-			}
+	bt.unlink(find)
+	return nil
+}
+
+// replace puts child in the place of tn below the parent of tn.
+func (bt *BSTree) replace(tn, child *Tnode) {
+	if child != nil {
+		child.parent = tn.parent
+	}
+
+	if tn.parent == nil {
+		bt.root = child
+	} else if tn.parent.lightChild == tn {
+		tn.parent.lightChild = child
+	} else {
+		tn.parent.rightChild = child
+	}
+}
+
+// unlink removes tn from the tree, the caller must hold the write lock. Other nodes keep
+// their data, so pointers to them stay valid.
+func (bt *BSTree) unlink(tn *Tnode) {
+	if bt.balancer != nil {
+		bt.balancer.unlinking(bt, tn)
+	}
+
+	// changed is the deepest node whose subtree lost a node.
+	changed := tn.parent
+	if tn.lightChild == nil { // Node to be removed has 0 child node or 1 right child node
+		bt.replace(tn, tn.rightChild)
+	} else if tn.rightChild == nil { // Node to be removed has 1 left child node
+		bt.replace(tn, tn.lightChild)
+	} else { // Node to be removed has 2 child node, which is replaced by its successor
+		leftMost, leftMostParent := findLeftMostNode(tn.rightChild, tn)
+		changed = leftMost
+		if leftMostParent != tn {
+			bt.replace(leftMost, leftMost.rightChild)
+			leftMost.rightChild = tn.rightChild
+			leftMost.rightChild.parent = leftMost
+			changed = leftMostParent
 		}
+		bt.replace(tn, leftMost)
+		leftMost.lightChild = tn.lightChild
+		leftMost.lightChild.parent = leftMost
 	}
+	bt.fixUp(changed)
+
+	tn.parent, tn.lightChild, tn.rightChild = nil, nil, nil
 	bt.size--
-	return nil
+	bt.mods++
+
+	if bt.balancer != nil {
+		bt.balancer.unlinked(bt, changed)
+	}
 }
 
 func findLeftMostNode(ret *Tnode, parent *Tnode) (*Tnode, *Tnode) {
-	// if using ret == nil to end recursion calls, it can't get the
-	// parent of the leftmost node.
-	if ret.lightChild == nil {
-		return ret, parent
+	for ret.lightChild != nil {
+		ret, parent = ret.lightChild, ret
 	}
 
-	return findLeftMostNode(ret.lightChild, ret)
+	return ret, parent
 }
 
 func findRightMostNode(ret *Tnode, parent *Tnode) (*Tnode, *Tnode) {
-	if ret.rightChild == nil {
-		return ret, parent
+	for ret.rightChild != nil {
+		ret, parent = ret.rightChild, ret
 	}
 
-	return findRightMostNode(ret.rightChild, ret)
+	return ret, parent
 }
 
 // Update updates the value associated with key to val. If the tree is empty, returns ErrEmptyTree. If not found, returns ErrNotExist.
+// Set must not change the key of the data, use ReKey for that. If it does, Update moves the data to where its new key belongs
+// and returns ErrKeyChanged, unless the new key is already used by other data, in which case the updated data is taken out
+// of the tree, as it can't stay where its old key belongs, and ErrDataExists is returned.
 func (bt *BSTree) Update(key interface{}, val interface{}) error {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	_, err := bt.update(key, val)
+	return err
+}
+
+// update does the work of Update, the caller must hold the write lock. Besides the error, it
+// returns the node of the updated data if its key didn't change, or the last node visited if
+// key wasn't found.
+func (bt *BSTree) update(key interface{}, val interface{}) (*Tnode, error) {
 	if bt.root == nil && bt.size == 0 {
-		return container.ErrEmptyTree
+		return nil, container.ErrEmptyTree
 	}
 
-	find, _ := lookup(bt.root, nil, key)
+	find, last := bt.descend(key)
 	if find == nil {
-		return container.ErrNotExist
+		return last, container.ErrNotExist
 	}
 	v := find.data.(container.Interface)
 	v.Set(val)
-	return nil
+	if v.Find(key) {
+		if bt.monoid != nil {
+			bt.fixUp(find)
+		}
+		return find, nil
+	}
+
+	bt.unlink(find)
+	if err := bt.insert(v); err != nil {
+		return nil, err
+	}
+	bt.size++
+	return nil, container.ErrKeyChanged
 }
 
-func inorderTraversal(tn *Tnode, ch chan<- interface{}) {
-	if tn == nil {
-		return
+// ReKey replaces the data found by oldKey with newValue, which may have a different key, moving it to where it belongs.
+// If the tree is empty, returns ErrEmptyTree. If oldKey is not found, returns ErrNotExist. If the key of newValue is used
+// by data other than the one being replaced, returns ErrDataExists. The tree is only changed if ReKey returns nil.
+func (bt *BSTree) ReKey(oldKey interface{}, newValue container.Interface) error {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if bt.root == nil && bt.size == 0 {
+		return container.ErrEmptyTree
+	}
+
+	find := bt.lookup(oldKey)
+	if find == nil {
+		return container.ErrNotExist
 	}
+	if other := bt.lookup(newValue); other != nil && other != find {
+		return container.ErrDataExists
+	}
+
+	bt.unlink(find)
+	if err := bt.insert(newValue); err != nil {
+		return err
+	}
+	bt.size++
+	return nil
+}
+
+// emitFunc sends data of a traversal, it returns false if the traversal must stop.
+type emitFunc func(data interface{}) bool
+
+func inorderTraversal(tn *Tnode, emit emitFunc) {
+	ls := stack.NewLStack()
+
+	for tn != nil || !ls.Empty() {
+		for ; tn != nil; tn = tn.lightChild {
+			ls.Push(tn)
+		}
 
-	inorderTraversal(tn.lightChild, ch)
-	ch <- tn.data
-	inorderTraversal(tn.rightChild, ch)
+		tn = ls.Pop().(*Tnode)
+		if !emit(tn.data) {
+			return
+		}
+		tn = tn.rightChild
+	}
 }
 
-func preorderTraversal(tn *Tnode, ch chan<- interface{}) {
+func preorderTraversal(tn *Tnode, emit emitFunc) {
 	if tn == nil {
 		return
 	}
 
-	ch <- tn.data
-	preorderTraversal(tn.lightChild, ch)
-	preorderTraversal(tn.rightChild, ch)
+	ls := stack.NewLStack()
+	ls.Push(tn)
+
+	for !ls.Empty() {
+		tn = ls.Pop().(*Tnode)
+		if !emit(tn.data) {
+			return
+		}
+		if tn.rightChild != nil {
+			ls.Push(tn.rightChild)
+		}
+		if tn.lightChild != nil {
+			ls.Push(tn.lightChild)
+		}
+	}
 }
 
-func postorderTraversal(tn *Tnode, ch chan<- interface{}) {
-	if tn == nil {
-		return
+func postorderTraversal(tn *Tnode, emit emitFunc) {
+	ls := stack.NewLStack()
+	var last *Tnode
+
+	for tn != nil || !ls.Empty() {
+		for ; tn != nil; tn = tn.lightChild {
+			ls.Push(tn)
+		}
+
+		// The top of stack is visited once its right subtree is empty or has just been visited.
+		top := ls.Pop().(*Tnode)
+		if top.rightChild != nil && top.rightChild != last {
+			ls.Push(top)
+			tn = top.rightChild
+			continue
+		}
+
+		if !emit(top.data) {
+			return
+		}
+		last = top
 	}
+}
+
+// morrisInorderTraversal threads the rightmost node of every left subtree to its successor
+// while descending, and removes the thread when it's followed back. It always walks the whole
+// tree so that no thread is left behind, so emit can't stop it.
+func morrisInorderTraversal(tn *Tnode, emit func(data interface{})) {
+	for tn != nil {
+		if tn.lightChild == nil {
+			emit(tn.data)
+			tn = tn.rightChild
+			continue
+		}
 
-	postorderTraversal(tn.lightChild, ch)
-	postorderTraversal(tn.rightChild, ch)
-	ch <- tn.data
+		pred := tn.lightChild
+		for pred.rightChild != nil && pred.rightChild != tn {
+			pred = pred.rightChild
+		}
+
+		if pred.rightChild == nil {
+			pred.rightChild = tn
+			tn = tn.lightChild
+		} else {
+			pred.rightChild = nil
+			emit(tn.data)
+			tn = tn.rightChild
+		}
+	}
 }
 
-func levelTraversal(tn *Tnode, ch chan<- interface{}) {
+func levelTraversal(tn *Tnode, emit emitFunc) {
 	if tn == nil {
 		return
 	}
@@ -263,7 +502,9 @@ func levelTraversal(tn *Tnode, ch chan<- interface{}) {
 
 	for !lq.Empty() {
 		ret := lq.LeQueue().(*Tnode)
-		ch <- ret.data
+		if !emit(ret.data) {
+			return
+		}
 		if ret.lightChild != nil {
 			lq.EnQueue(ret.lightChild)
 		}
@@ -274,26 +515,63 @@ func levelTraversal(tn *Tnode, ch chan<- interface{}) {
 }
 
 // Traversal traverses the tree using predefined traverse method, which specified by the predefined constants,
-// such as InorderTrav, PreorderTrav, PostorderTrav, LevelTrav.
+// such as InorderTrav, PreorderTrav, PostorderTrav, LevelTrav, MorrisInorderTrav. If the tree is structurally
+// modified before the traversal ends, it stops and ErrConcurrentModification is sent as the last value.
 func (bt *BSTree) Traversal(TravType TraversalType) <-chan interface{} {
 	ch := make(chan interface{})
 
+	bt.rw.RLock()
+	mods := bt.mods
+	bt.rw.RUnlock()
+
 	go func() {
 		defer close(ch)
 
-		if bt.root == nil && bt.size == 0 {
+		if TravType == MorrisInorderTrav {
+			// The threads must not be seen by anyone, so the tree stays locked until they are
+			// all removed, that is until the walk ends.
+			bt.rw.Lock()
+			defer bt.rw.Unlock()
+			if bt.mods != mods {
+				ch <- container.ErrConcurrentModification
+				return
+			}
+			morrisInorderTraversal(bt.root, func(data interface{}) {
+				ch <- data
+			})
+			return
+		}
+
+		// The tree is only locked while walking, not while blocked on sending.
+		bt.rw.RLock()
+		defer bt.rw.RUnlock()
+		emit := func(data interface{}) bool {
+			modified := bt.mods != mods
+			if modified {
+				data = container.ErrConcurrentModification
+			}
+
+			bt.rw.RUnlock()
+			ch <- data
+			bt.rw.RLock()
+			return !modified
+		}
+
+		if bt.mods != mods {
+			// emit sends ErrConcurrentModification instead of nil.
+			emit(nil)
 			return
 		}
 
 		switch TravType {
 		case InorderTrav:
-			inorderTraversal(bt.root, ch)
+			inorderTraversal(bt.root, emit)
 		case PreorderTrav:
-			preorderTraversal(bt.root, ch)
+			preorderTraversal(bt.root, emit)
 		case PostorderTrav:
-			postorderTraversal(bt.root, ch)
+			postorderTraversal(bt.root, emit)
 		case LevelTrav:
-			levelTraversal(bt.root, ch)
+			levelTraversal(bt.root, emit)
 		}
 	}()
 
@@ -301,11 +579,21 @@ func (bt *BSTree) Traversal(TravType TraversalType) <-chan interface{} {
 }
 
 // TravWith traverses the tree using user-defined function. Note that if you use recursion inside anonymouse function you
-// must declare it first.
+// must declare it first. If trave panics, the traversal stops and a *container.CallbackPanic is sent as the last
+// value before the channel is closed. The tree is locked for reading until trave returns, so the receiver must not
+// modify the tree before draining the channel.
 func (bt *BSTree) TravWith(trave TravFunc) <-chan interface{} {
 	ch := make(chan interface{})
 	go func() {
 		defer close(ch)
+		defer func() {
+			if r := recover(); r != nil {
+				ch <- &container.CallbackPanic{Value: r, Stack: debug.Stack()}
+			}
+		}()
+
+		bt.rw.RLock()
+		defer bt.rw.RUnlock()
 		if bt.root == nil && bt.size == 0 {
 			return
 		}
@@ -322,8 +610,11 @@ func (bt *BSTree) Size() int {
 
 // Reset drops all of data in the tree bt and back to its initial state.
 func (bt *BSTree) Reset() {
+	bt.rw.Lock()
 	bt.root = nil
 	bt.size = 0
+	bt.mods++
+	bt.rw.Unlock()
 }
 
 // Empty returns true if the tree is empty tree, otherwise false.
@@ -331,24 +622,43 @@ func (bt *BSTree) Empty() bool {
 	return bt.root == nil && bt.size == 0
 }
 
+// nodeDepth pairs a node with its depth below the node the walk started from.
+type nodeDepth struct {
+	tn    *Tnode
+	depth int
+}
+
 func getHeight(tn *Tnode) int {
 	if tn == nil {
 		return -1
 	}
 
-	lHeight := getHeight(tn.lightChild)
-	rHeight := getHeight(tn.rightChild)
+	var height int
+	ls := stack.NewLStack()
+	ls.Push(nodeDepth{tn, 0})
 
-	if lHeight < rHeight {
-		return rHeight + 1
+	for !ls.Empty() {
+		nd := ls.Pop().(nodeDepth)
+		if nd.depth > height {
+			height = nd.depth
+		}
+		if nd.tn.lightChild != nil {
+			ls.Push(nodeDepth{nd.tn.lightChild, nd.depth + 1})
+		}
+		if nd.tn.rightChild != nil {
+			ls.Push(nodeDepth{nd.tn.rightChild, nd.depth + 1})
+		}
 	}
 
-	return lHeight + 1
+	return height
 }
 
 // Height returns the height of tree, it also refers to the height of root, which is the number of edges
 // on the longest downward path between the root and a leaf. If return -1, it means that the tree is empty.
 func (bt *BSTree) Height() int {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
 	if bt.root == nil && bt.size == 0 {
 		return -1
 	}
@@ -359,11 +669,14 @@ func (bt *BSTree) Height() int {
 // HeightOf returns the height of specified node of tree, which is the largest number of edges in the
 // path from that node to leaf. If data can't be found using key, return -1 and ErrNotExist error.
 func (bt *BSTree) HeightOf(key interface{}) (int, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
 	if bt.root == nil && bt.size == 0 {
 		return -1, container.ErrEmptyTree
 	}
 
-	find, _ := lookup(bt.root, nil, key)
+	find := bt.lookup(key)
 	if find == nil {
 		return -1, container.ErrNotExist
 	}
@@ -371,32 +684,31 @@ func (bt *BSTree) HeightOf(key interface{}) (int, error) {
 	return getHeight(find), nil
 }
 
-func getDepth(from *Tnode, key interface{}) (int, error) {
-	if from == nil {
-		return -1, container.ErrNotExist
-	}
-	v := from.data.(container.Interface)
-	if v.Find(key) {
-		return 0, nil
-	}
-
+func (bt *BSTree) getDepth(key interface{}) (int, error) {
 	var d int
-	var err error
-	toRight := v.Less(key)
-	if toRight {
-		d, err = getDepth(from.rightChild, key)
-	} else {
-		d, err = getDepth(from.lightChild, key)
-	}
+	for from := bt.root; from != nil; d++ {
+		v := from.data.(container.Interface)
+		bt.observe(v, key)
+		if v.Find(key) {
+			return d, nil
+		}
 
-	if err != nil {
-		return -1, container.ErrNotExist
+		toRight := bt.goesRight(v, key)
+		if toRight {
+			from = from.rightChild
+		} else {
+			from = from.lightChild
+		}
 	}
-	return d + 1, nil
+
+	return -1, container.ErrNotExist
 }
 
 // Depth returns the depth of the tree, which is equal to the height of the tree.
 func (bt *BSTree) Depth() int {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
 	if bt.root == nil && bt.size == 0 {
 		return -1
 	}
@@ -407,16 +719,22 @@ func (bt *BSTree) Depth() int {
 // DepthOf returns the depth of data found by key in the tree, which is the number of
 // edges in the path from the root to that node.
 func (bt *BSTree) DepthOf(key interface{}) (int, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
 	if bt.root == nil && bt.size == 0 {
 		return -1, container.ErrEmptyTree
 	}
 
-	return getDepth(bt.root, key)
+	return bt.getDepth(key)
 }
 
 // FullTree returns true if the tree is full tree, note that beacuse of property of
 // binary tree, a full tree is also a complete tree.
 func (bt *BSTree) FullTree() bool {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
 	h := getHeight(bt.root)
 	if bt.size == int(math.Pow(2, float64(h+1)))-1 {
 		return true
@@ -424,6 +742,75 @@ func (bt *BSTree) FullTree() bool {
 	return false
 }
 
+// bound records a node together with the nearest ancestors that bound its key from below and above.
+type bound struct {
+	tn, lower, upper *Tnode
+}
+
+// Validate checks the invariants of the tree: every node lies on the side of its ancestors that
+// Less directs it to, parent pointers match child pointers, no node is reachable twice, size
+// equals the number of nodes, and every node holds the count and aggregate of its subtree.
+// It returns nil if the tree is consistent, otherwise an error wrapping ErrCorrupted.
+func (bt *BSTree) Validate() error {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil {
+		if bt.size != 0 {
+			return fmt.Errorf("%w: empty tree has size %d", container.ErrCorrupted, bt.size)
+		}
+		return nil
+	}
+	if bt.root.parent != nil {
+		return fmt.Errorf("%w: root %v has a parent", container.ErrCorrupted, bt.root.data)
+	}
+
+	visited := make(map[*Tnode]bool)
+	ls := stack.NewLStack()
+	ls.Push(bound{tn: bt.root})
+
+	for !ls.Empty() {
+		b := ls.Pop().(bound)
+		if visited[b.tn] {
+			return fmt.Errorf("%w: node %v is reachable more than once", container.ErrCorrupted, b.tn.data)
+		}
+		visited[b.tn] = true
+		if err := bt.checkAugment(b.tn); err != nil {
+			return err
+		}
+		if bt.balancer != nil {
+			if err := bt.balancer.check(b.tn); err != nil {
+				return err
+			}
+		}
+
+		if b.lower != nil && !bt.goesRight(b.lower.data.(container.Lesser), b.tn.data) {
+			return fmt.Errorf("%w: %v is in the right subtree of %v", container.ErrCorrupted, b.tn.data, b.lower.data)
+		}
+		if b.upper != nil && bt.goesRight(b.upper.data.(container.Lesser), b.tn.data) {
+			return fmt.Errorf("%w: %v is in the left subtree of %v", container.ErrCorrupted, b.tn.data, b.upper.data)
+		}
+
+		if b.tn.rightChild != nil {
+			if b.tn.rightChild.parent != b.tn {
+				return fmt.Errorf("%w: parent of %v is not %v", container.ErrCorrupted, b.tn.rightChild.data, b.tn.data)
+			}
+			ls.Push(bound{b.tn.rightChild, b.tn, b.upper})
+		}
+		if b.tn.lightChild != nil {
+			if b.tn.lightChild.parent != b.tn {
+				return fmt.Errorf("%w: parent of %v is not %v", container.ErrCorrupted, b.tn.lightChild.data, b.tn.data)
+			}
+			ls.Push(bound{b.tn.lightChild, b.lower, b.tn})
+		}
+	}
+
+	if len(visited) != bt.size {
+		return fmt.Errorf("%w: size is %d but tree has %d nodes", container.ErrCorrupted, bt.size, len(visited))
+	}
+	return nil
+}
+
 // Compare compares whether two trees is the same, if be the same, return true, otherwise false.
 func Compare(bt1, bt2 *BSTree) bool {
 	// As long as the data to be inserted are the same, the result of inorder traversal of binary tree is irrelevant with
@@ -0,0 +1,93 @@
+package tree_test
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+// FuzzBSTree decodes ops as a sequence of (opcode, key) byte pairs, applies them to a BSTree
+// and to a map used as reference model, and checks that both agree after every step.
+func FuzzBSTree(f *testing.F) {
+	f.Add([]byte{0, 5, 0, 3, 0, 7, 0, 4, 1, 5, 2, 3, 1, 3})
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 0, 4, 1, 2, 1, 1, 1, 4, 1, 3})
+	f.Add([]byte{0, 8, 0, 4, 0, 12, 0, 2, 0, 6, 0, 10, 0, 14, 1, 8, 1, 4, 1, 12})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		bt := tree.NewBSTree()
+		model := make(map[int]string)
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			key := int(ops[i+1] % 32)
+			switch ops[i] % 3 {
+			case 0:
+				name := strconv.Itoa(i)
+				err := bt.Insert(&testdata.Corp{ID: key, Name: name})
+				if _, ok := model[key]; ok {
+					if err != container.ErrDataExists {
+						t.Fatalf("Insert(%v): %v != %v", key, err, container.ErrDataExists)
+					}
+				} else {
+					if err != nil {
+						t.Fatalf("Insert(%v): %v != nil", key, err)
+					}
+					model[key] = name
+				}
+			case 1:
+				err := bt.Delete(key)
+				if _, ok := model[key]; ok {
+					if err != nil {
+						t.Fatalf("Delete(%v): %v != nil", key, err)
+					}
+					delete(model, key)
+				} else if err == nil {
+					t.Fatalf("Delete(%v) of missing key succeeded", key)
+				}
+			case 2:
+				name := "updated" + strconv.Itoa(i)
+				err := bt.Update(key, name)
+				if _, ok := model[key]; ok {
+					if err != nil {
+						t.Fatalf("Update(%v): %v != nil", key, err)
+					}
+					model[key] = name
+				} else if err == nil {
+					t.Fatalf("Update(%v) of missing key succeeded", key)
+				}
+			}
+
+			if err := bt.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			checkAgainstModel(t, bt, model)
+		}
+	})
+}
+
+func checkAgainstModel(t *testing.T, bt *tree.BSTree, model map[int]string) {
+	keys := make([]int, 0, len(model))
+	for k := range model {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	if bt.Size() != len(keys) {
+		t.Fatalf("%v != %v", bt.Size(), len(keys))
+	}
+
+	var j int
+	for itf := range bt.Traversal(tree.InorderTrav) {
+		pn := itf.(*testdata.Corp)
+		if j >= len(keys) || pn.ID != keys[j] || pn.Name != model[keys[j]] {
+			t.Fatalf("unexpected %v at position %v", *pn, j)
+		}
+		j++
+	}
+	if j != len(keys) {
+		t.Fatalf("%v != %v", j, len(keys))
+	}
+}
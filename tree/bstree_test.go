@@ -1,6 +1,7 @@
 package tree_test
 
 import (
+	"errors"
 	"math/rand"
 	"strings"
 	"testing"
@@ -439,3 +440,293 @@ func compareIntSlice(s1, s2 []int) bool {
 	}
 	return true
 }
+
+func TestBSTreeValidate(t *testing.T) {
+	bt := tree.NewBSTree()
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	ri := r.Perm(len(testCase))
+	bt, _ = createTree(ri)
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	for _, iv := range r.Perm(len(testCase)) {
+		bt.Delete(testCase[iv].ID)
+		if err := bt.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+}
+
+func TestBSTreeWithChecker(t *testing.T) {
+	c := container.NewChecker(0)
+	bt := tree.NewBSTree(tree.WithChecker(c))
+	for _, iv := range r.Perm(len(testCase)) {
+		bt.Insert(&testCase[iv])
+	}
+	for _, iv := range r.Perm(len(testCase)) {
+		bt.Search(&testCase[iv])
+	}
+
+	if violations := c.Violations(); violations != nil {
+		t.Errorf("%v != nil", violations)
+	}
+
+	// Corp silently returns false from Less and Find for keys of unknown type.
+	c = container.NewChecker(0)
+	bt = tree.NewBSTree(tree.WithChecker(c))
+	bt.Insert(&testdata.Corp{ID: 1, Name: "Alphabet"})
+	bt.Insert(&testdata.Corp{ID: 2, Name: "Intel"})
+	bt.Insert(&unordered{testdata.Corp{ID: 3, Name: "Dell"}})
+	if len(c.Violations()) == 0 {
+		t.Errorf("inconsistent Less is not reported")
+	}
+
+	// Corp can't be checked against a key of unknown type, nor against ints.
+	c = container.NewChecker(0)
+	bt = tree.NewBSTree(tree.WithChecker(c))
+	bt.Insert(&testdata.Corp{ID: 1, Name: "Alphabet"})
+	bt.Insert(&testdata.Corp{ID: 2, Name: "Intel"})
+	if _, err := bt.Search("Intel"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	bt.Search(2)
+	bt.Search(1)
+	violations := c.Violations()
+	if len(violations) != 2 || violations[0].Rule != container.RuleKeyType || violations[0].Values[1] != "Intel" {
+		t.Errorf("%v are not the key types string and int", violations)
+	}
+}
+
+// unordered wraps a Corp, which the type switches in Less and Find of Corp don't recognise.
+type unordered struct {
+	testdata.Corp
+}
+
+func TestBSTreeTravWithPanic(t *testing.T) {
+	defidx := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	bt, _ := createTree(defidx)
+
+	ch := bt.TravWith(func(tn *tree.Tnode, ch chan<- interface{}) {
+		ch <- tn.GetData()
+		ch <- tn.GetLchild().GetLchild().GetLchild().GetLchild().GetData()
+	})
+
+	var values []interface{}
+	for itf := range ch {
+		values = append(values, itf)
+	}
+
+	if len(values) != 2 || values[0].(*testdata.Corp) != &testCase[0] {
+		t.Fatalf("unexpected values %v", values)
+	}
+	err, ok := values[1].(error)
+	if !ok || !errors.Is(err, container.ErrCallbackPanic) {
+		t.Errorf("%v != %v", values[1], container.ErrCallbackPanic)
+	}
+}
+
+func TestBSTreeMorrisTraversal(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		bt, _ := createTree(r.Perm(len(testCase)))
+
+		var inorder []interface{}
+		for itf := range bt.Traversal(tree.InorderTrav) {
+			inorder = append(inorder, itf)
+		}
+
+		var j int
+		for itf := range bt.Traversal(tree.MorrisInorderTrav) {
+			if j >= len(inorder) || itf != inorder[j] {
+				t.Errorf("unexpected %v at position %v", itf, j)
+			}
+			j++
+		}
+		if j != len(inorder) {
+			t.Errorf("%v != %v", j, len(inorder))
+		}
+
+		if err := bt.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+}
+
+func TestBSTreeMorrisTraversalConcurrentSearch(t *testing.T) {
+	bt, _ := createTree(r.Perm(len(testCase)))
+
+	// Searches running alongside the traversal wait for it instead of following its threads,
+	// which -race would report.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if _, err := bt.Search(testCase[r.Intn(len(testCase))].ID); err != nil {
+				t.Errorf("%v != nil", err)
+			}
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		var count int
+		for range bt.Traversal(tree.MorrisInorderTrav) {
+			count++
+		}
+		if count != len(testCase) {
+			t.Errorf("%v != %v", count, len(testCase))
+		}
+	}
+	<-done
+
+	// The tree is free again once the channel is drained.
+	ch := bt.Traversal(tree.MorrisInorderTrav)
+	for range ch {
+	}
+	if err := bt.Insert(&testdata.Corp{ID: 100}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+// TestBSTreeDegenerate runs the tree algorithms on a tree in which every node only has a
+// right child, which recursive implementations can't handle at this size.
+func TestBSTreeDegenerate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping degenerate tree in short mode")
+	}
+
+	const n = 1000000
+	seq := make([]container.Interface, n)
+	for i := range seq {
+		seq[i] = &testdata.Corp{ID: i}
+	}
+
+	// Preorder and inorder are the same for a right-leaning chain, and unlike Insert,
+	// FromPreInorder builds it in linear time.
+	bt, err := tree.FromPreInorder(seq, seq)
+	if err != nil {
+		t.Fatalf("%v != nil", err)
+	}
+	if err := bt.Validate(); err != nil {
+		t.Fatalf("%v != nil", err)
+	}
+
+	if h := bt.Height(); h != n-1 {
+		t.Errorf("%v != %v", h, n-1)
+	}
+	if d, err := bt.DepthOf(n - 1); err != nil || d != n-1 {
+		t.Errorf("(%v != nil) or (%v != %v)", err, d, n-1)
+	}
+	if err := bt.Insert(&testdata.Corp{ID: n}); err != nil || bt.Size() != n+1 {
+		t.Errorf("(%v != nil) or (%v != %v)", err, bt.Size(), n+1)
+	}
+	if err := bt.Delete(n); err != nil || bt.Size() != n {
+		t.Errorf("(%v != nil) or (%v != %v)", err, bt.Size(), n)
+	}
+
+	for _, travType := range []tree.TraversalType{tree.InorderTrav, tree.MorrisInorderTrav, tree.PostorderTrav} {
+		var count int
+		for range bt.Traversal(travType) {
+			count++
+		}
+		if count != n {
+			t.Errorf("%v != %v", count, n)
+		}
+	}
+}
+
+func TestBSTreeTraversalConcurrentModification(t *testing.T) {
+	for i := 0; i <= 3; i++ {
+		bt, _ := createTree(defidx)
+
+		ch := bt.Traversal(tree.TraversalType(i))
+		<-ch
+		bt.Insert(&testdata.Corp{ID: 11})
+
+		var last interface{}
+		for itf := range ch {
+			last = itf
+		}
+		if last != container.ErrConcurrentModification {
+			t.Errorf("%v != %v", last, container.ErrConcurrentModification)
+		}
+	}
+
+	bt, _ := createTree(defidx)
+	ch := bt.Traversal(tree.InorderTrav)
+	bt.Reset()
+	if itf := <-ch; itf != container.ErrConcurrentModification {
+		t.Errorf("%v != %v", itf, container.ErrConcurrentModification)
+	}
+	if _, ok := <-ch; ok {
+		t.Errorf("channel is not closed")
+	}
+}
+
+func TestBSTreeUpdateKeyChanged(t *testing.T) {
+	bt := tree.NewBSTree()
+	for _, id := range []int{5, 3, 7, 1, 4} {
+		bt.Insert(&testdata.IDSetter{testdata.Corp{ID: id}})
+	}
+
+	if err := bt.Update(3, 6); err != container.ErrKeyChanged {
+		t.Errorf("%v != %v", err, container.ErrKeyChanged)
+	}
+	if _, err := bt.Search(6); err != nil || bt.Size() != 5 {
+		t.Errorf("(%v != nil) or (%v != 5)", err, bt.Size())
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	// The new key is used by other data, which stays, and the updated data is taken out.
+	other, _ := bt.Search(7)
+	if err := bt.Update(4, 7); err != container.ErrDataExists || bt.Size() != 4 {
+		t.Errorf("(%v != %v) or (%v != 4)", err, container.ErrDataExists, bt.Size())
+	}
+	if itf, err := bt.Search(7); err != nil || itf != other {
+		t.Errorf("(%v != nil) or (%v != %v)", err, itf, other)
+	}
+	if _, err := bt.Search(4); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestBSTreeReKey(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	if err := bt.ReKey(3, &testdata.Corp{ID: 11, Name: "Nvidia"}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if _, err := bt.Search(3); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if v, err := bt.Search(11); err != nil || v.(*testdata.Corp).Name != "Nvidia" {
+		t.Errorf("(%v != nil) or (%v != Nvidia)", err, v)
+	}
+	if bt.Size() != len(testCase) {
+		t.Errorf("%v != %v", bt.Size(), len(testCase))
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	// Keeping the key is fine, taking the key of other data is not.
+	if err := bt.ReKey(5, &testdata.Corp{ID: 5, Name: "Netflix Inc"}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if err := bt.ReKey(5, &testdata.Corp{ID: 7}); err != container.ErrDataExists {
+		t.Errorf("%v != %v", err, container.ErrDataExists)
+	}
+	if err := bt.ReKey(3, &testdata.Corp{ID: 3}); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+}
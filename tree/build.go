@@ -0,0 +1,253 @@
+package tree
+
+import (
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/queue"
+	"github.com/NzKSO/container/stack"
+)
+
+// matches reports whether traversing bt in order travType yields the values of seq, which are
+// matched by Find, so that values of types that can't be compared with == are accepted.
+func (bt *BSTree) matches(travType TraversalType, seq []container.Interface) bool {
+	var i int
+	ch := bt.Traversal(travType)
+	for itf := range ch {
+		if i >= len(seq) || !seq[i].Find(itf) {
+			for range ch {
+			}
+			return false
+		}
+		i++
+	}
+	return i == len(seq)
+}
+
+// before reports whether data belongs to the left of v, it returns ErrDataExists if v is found by data.
+func (bt *BSTree) before(v, data container.Interface) (bool, error) {
+	bt.observe(v, data)
+	if v.Find(data) {
+		return false, container.ErrDataExists
+	}
+	return !bt.goesRight(v, data), nil
+}
+
+// link makes child the left or right child of parent.
+func link(parent, child *Tnode, left bool) {
+	if left {
+		parent.lightChild = child
+	} else {
+		parent.rightChild = child
+	}
+	child.parent = parent
+}
+
+// finish completes a tree of n nodes linked below root without Insert.
+func (bt *BSTree) finish(root *Tnode, n int) *BSTree {
+	bt.root, bt.size = root, n
+	bt.fixAll(root)
+	return bt
+}
+
+// fromParentsFirst builds the tree of a sequence where every node comes before its descendants,
+// and all nodes of one subtree of a node come before all nodes of the other, first the left one,
+// or first the right one if rightFirst is true. The stack holds the nodes that may still get a
+// child on the side coming second, and bound is the nearest ancestor on that side of the next
+// node, which must be ordered before it. It takes O(n).
+func fromParentsFirst(seq []container.Interface, rightFirst bool, opts []Option) (*BSTree, error) {
+	bt := NewBSTree(opts...)
+	if len(seq) == 0 {
+		return bt, nil
+	}
+
+	root := &Tnode{data: seq[0]}
+	ls := stack.NewLStack()
+	ls.Push(root)
+	var bound *Tnode
+	for _, v := range seq[1:] {
+		if bound != nil {
+			left, err := bt.before(bound.data.(container.Interface), v)
+			if err != nil {
+				return nil, err
+			}
+			if left != rightFirst {
+				return nil, container.ErrInvalidTraversal
+			}
+		}
+
+		tn := &Tnode{data: v}
+		var parent, top *Tnode
+		for !ls.Empty() {
+			top = ls.Pop().(*Tnode)
+			left, err := bt.before(top.data.(container.Interface), v)
+			if err != nil {
+				return nil, err
+			}
+			if left != rightFirst {
+				ls.Push(top)
+				break
+			}
+			parent, top = top, nil
+		}
+
+		if parent != nil {
+			// v is the first node of the subtree coming second of parent.
+			link(parent, tn, rightFirst)
+			bound = parent
+		} else {
+			link(top, tn, !rightFirst)
+		}
+		ls.Push(tn)
+	}
+	return bt.finish(root, len(seq)), nil
+}
+
+// FromPreorder rebuilds the tree whose PreorderTrav traversal yields seq in O(n). It returns
+// ErrDataExists if seq repeats a key where the tree would hold it twice, and ErrInvalidTraversal
+// if seq is otherwise not the preorder traversal of any binary search tree.
+func FromPreorder(seq []container.Interface, opts ...Option) (*BSTree, error) {
+	return fromParentsFirst(seq, false, opts)
+}
+
+// FromPostorder rebuilds the tree whose PostorderTrav traversal yields seq in O(n). It returns
+// ErrDataExists if seq repeats a key where the tree would hold it twice, and ErrInvalidTraversal
+// if seq is otherwise not the postorder traversal of any binary search tree.
+func FromPostorder(seq []container.Interface, opts ...Option) (*BSTree, error) {
+	// Reversed postorder visits root, right subtree, left subtree, so parents still come first.
+	reversed := make([]container.Interface, len(seq))
+	for i, v := range seq {
+		reversed[len(seq)-1-i] = v
+	}
+	return fromParentsFirst(reversed, true, opts)
+}
+
+// levelSlot is a node of a tree built from its level order together with the nearest ancestors
+// bounding its subtree from below and above.
+type levelSlot struct {
+	tn, lower, upper *Tnode
+}
+
+// within reports whether data belongs between lower and upper, either of which may be nil.
+func (bt *BSTree) within(data container.Interface, lower, upper *Tnode) (bool, error) {
+	if lower != nil {
+		left, err := bt.before(lower.data.(container.Interface), data)
+		if err != nil || left {
+			return false, err
+		}
+	}
+	if upper != nil {
+		left, err := bt.before(upper.data.(container.Interface), data)
+		if err != nil || !left {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// FromLevelOrder rebuilds the tree whose LevelTrav traversal yields seq in O(n). It returns
+// ErrDataExists if seq repeats a key where the tree would hold it twice, and ErrInvalidTraversal
+// if seq is otherwise not the level order traversal of any binary search tree.
+func FromLevelOrder(seq []container.Interface, opts ...Option) (*BSTree, error) {
+	bt := NewBSTree(opts...)
+	if len(seq) == 0 {
+		return bt, nil
+	}
+
+	// The queue holds the nodes that may still get children, in level order, slot being the first
+	// of them. Each value becomes a child of the first node it fits below, the nodes before that
+	// one can't get any child anymore.
+	root := &Tnode{data: seq[0]}
+	lq := queue.NewLQueue()
+	slot := &levelSlot{tn: root}
+	for _, v := range seq[1:] {
+		tn := &Tnode{data: v}
+		for {
+			if slot == nil {
+				if lq.Empty() {
+					return nil, container.ErrInvalidTraversal
+				}
+				slot = lq.LeQueue().(*levelSlot)
+			}
+
+			if slot.tn.lightChild == nil {
+				fits, err := bt.within(v, slot.lower, slot.tn)
+				if err != nil {
+					return nil, err
+				}
+				if fits {
+					link(slot.tn, tn, true)
+					lq.EnQueue(&levelSlot{tn, slot.lower, slot.tn})
+					break
+				}
+			}
+
+			fits, err := bt.within(v, slot.tn, slot.upper)
+			if err != nil {
+				return nil, err
+			}
+			parent := slot
+			slot = nil
+			if fits {
+				link(parent.tn, tn, false)
+				lq.EnQueue(&levelSlot{tn, parent.tn, parent.upper})
+				break
+			}
+		}
+	}
+	return bt.finish(root, len(seq)), nil
+}
+
+// FromPreInorder rebuilds a binary tree from its preorder and inorder traversals. Values are
+// matched by Find, so their keys must be distinct, and Less is never called: the shape is taken from
+// the sequences alone. Search, Insert, Delete and Update only work on the result if it is a
+// binary search tree. It returns ErrInvalidTraversal if the sequences don't describe the same tree.
+func FromPreInorder(preorder, inorder []container.Interface, opts ...Option) (*BSTree, error) {
+	if len(preorder) != len(inorder) {
+		return nil, container.ErrInvalidTraversal
+	}
+
+	bt := NewBSTree(opts...)
+	if len(preorder) == 0 {
+		return bt, nil
+	}
+
+	// The stack holds the nodes whose right child is still to be attached, deepest on top.
+	bt.root = &Tnode{data: preorder[0]}
+	ls := stack.NewLStack()
+	ls.Push(bt.root)
+
+	var in int
+	for _, v := range preorder[1:] {
+		tn := &Tnode{data: v}
+		top := ls.Pop().(*Tnode)
+		if !inorder[in].Find(top.data) {
+			ls.Push(top)
+			top.lightChild = tn
+			tn.parent = top
+		} else {
+			// top and the ancestors popped with it have had their left subtree fully
+			// visited, the last one popped is the parent of tn.
+			parent := top
+			for in++; !ls.Empty() && in < len(inorder); in++ {
+				next := ls.Pop().(*Tnode)
+				if !inorder[in].Find(next.data) {
+					ls.Push(next)
+					break
+				}
+				parent = next
+			}
+			parent.rightChild = tn
+			tn.parent = parent
+		}
+		ls.Push(tn)
+
+		if in >= len(inorder) {
+			return nil, container.ErrInvalidTraversal
+		}
+	}
+	bt.finish(bt.root, len(preorder))
+
+	if !bt.matches(PreorderTrav, preorder) || !bt.matches(InorderTrav, inorder) {
+		return nil, container.ErrInvalidTraversal
+	}
+	return bt, nil
+}
@@ -0,0 +1,157 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+func collect(bt *tree.BSTree, travType tree.TraversalType) []container.Interface {
+	var seq []container.Interface
+	for itf := range bt.Traversal(travType) {
+		seq = append(seq, itf.(container.Interface))
+	}
+	return seq
+}
+
+func TestFromTraversal(t *testing.T) {
+	builders := []struct {
+		travType tree.TraversalType
+		build    func([]container.Interface, ...tree.Option) (*tree.BSTree, error)
+	}{
+		{tree.PreorderTrav, tree.FromPreorder},
+		{tree.PostorderTrav, tree.FromPostorder},
+		{tree.LevelTrav, tree.FromLevelOrder},
+	}
+
+	for i := 0; i < 10; i++ {
+		bt, _ := createTree(r.Perm(len(testCase)))
+		for _, b := range builders {
+			rebuilt, err := b.build(collect(bt, b.travType))
+			if err != nil || !tree.Compare(bt, rebuilt) || rebuilt.Size() != bt.Size() {
+				t.Errorf("(%v != nil) or rebuilt tree differs from traversal %v", err, b.travType)
+			}
+			if err := rebuilt.Validate(); err != nil {
+				t.Errorf("%v != nil", err)
+			}
+		}
+
+		rebuilt, err := tree.FromPreInorder(collect(bt, tree.PreorderTrav), collect(bt, tree.InorderTrav))
+		if err != nil || !tree.Compare(bt, rebuilt) || rebuilt.Size() != bt.Size() {
+			t.Errorf("(%v != nil) or rebuilt tree differs", err)
+		}
+	}
+
+	empty, err := tree.FromPreorder(nil)
+	if err != nil || !empty.Empty() {
+		t.Errorf("(%v != nil) or tree is not empty", err)
+	}
+}
+
+func TestFromTraversalInvalid(t *testing.T) {
+	// 3 can't follow 7 in the preorder traversal of a tree rooted at 5.
+	seq := []container.Interface{&testCase[0], &testCase[2], &testCase[1]}
+	if _, err := tree.FromPreorder(seq); err != container.ErrInvalidTraversal {
+		t.Errorf("%v != %v", err, container.ErrInvalidTraversal)
+	}
+	// 7 is on the same level as 0, so it must come before 3, the child of 0.
+	seq = []container.Interface{&testCase[0], &testCase[3], &testCase[1], &testCase[2]}
+	if _, err := tree.FromLevelOrder(seq); err != container.ErrInvalidTraversal {
+		t.Errorf("%v != %v", err, container.ErrInvalidTraversal)
+	}
+
+	seq = []container.Interface{&testCase[0], &testCase[1], &testCase[0]}
+	if _, err := tree.FromPostorder(seq); err != container.ErrDataExists {
+		t.Errorf("%v != %v", err, container.ErrDataExists)
+	}
+
+	pre := []container.Interface{&testCase[0], &testCase[1], &testCase[2]}
+	in := []container.Interface{&testCase[2], &testCase[0], &testCase[1]}
+	if _, err := tree.FromPreInorder(pre, in); err != container.ErrInvalidTraversal {
+		t.Errorf("%v != %v", err, container.ErrInvalidTraversal)
+	}
+	if _, err := tree.FromPreInorder(pre, in[:2]); err != container.ErrInvalidTraversal {
+		t.Errorf("%v != %v", err, container.ErrInvalidTraversal)
+	}
+}
+
+func TestFromPreInorderGeneral(t *testing.T) {
+	// Neither sequence needs to come from a binary search tree: 5 has 7 as its left child.
+	pre := []container.Interface{&testCase[0], &testCase[2], &testCase[4], &testCase[1]}
+	in := []container.Interface{&testCase[4], &testCase[2], &testCase[0], &testCase[1]}
+
+	bt, err := tree.FromPreInorder(pre, in)
+	if err != nil {
+		t.Fatalf("%v != nil", err)
+	}
+	if bt.Size() != len(pre) {
+		t.Errorf("%v != %v", bt.Size(), len(pre))
+	}
+
+	want := []container.Interface{&testCase[4], &testCase[2], &testCase[1], &testCase[0]}
+	got := collect(bt, tree.PostorderTrav)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%v != %v", got[i], want[i])
+		}
+	}
+}
+
+// chain is a value that can't be compared with ==, as it holds a slice.
+type chain struct {
+	ID   int
+	tags []string
+}
+
+func (c chain) Less(kv interface{}) bool {
+	return c.ID <= kv.(chain).ID
+}
+
+func (c chain) Find(key interface{}) bool {
+	return c.ID == key.(chain).ID
+}
+
+func (c chain) Set(interface{}) {}
+
+func TestFromTraversalDegenerate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping degenerate tree in short mode")
+	}
+
+	// Rebuilding a chain of a million nodes takes linear time, while inserting them one by one
+	// would take quadratic time.
+	const n = 1000000
+	seq := make([]container.Interface, n)
+	for i := range seq {
+		seq[i] = &testdata.Corp{ID: i}
+	}
+	for _, build := range []func([]container.Interface, ...tree.Option) (*tree.BSTree, error){
+		tree.FromPreorder, tree.FromLevelOrder,
+	} {
+		bt, err := build(seq)
+		if err != nil || bt.Size() != n || bt.Height() != n-1 {
+			t.Fatalf("(%v != nil) or (%v != %v) or (%v != %v)", err, bt.Size(), n, bt.Height(), n-1)
+		}
+	}
+	if bt, err := tree.FromPostorder(seq); err != nil || bt.Height() != n-1 {
+		t.Fatalf("(%v != nil) or (%v != %v)", err, bt.Height(), n-1)
+	}
+}
+
+func TestFromTraversalIncomparable(t *testing.T) {
+	bt := tree.NewBSTree()
+	for _, id := range []int{5, 3, 7} {
+		bt.Insert(chain{id, []string{"tag"}})
+	}
+	pre, in := collect(bt, tree.PreorderTrav), collect(bt, tree.InorderTrav)
+
+	rebuilt, err := tree.FromPreInorder(pre, in)
+	if err != nil || rebuilt.Size() != 3 {
+		t.Fatalf("(%v != nil) or rebuilt tree is not of size 3", err)
+	}
+	if rebuilt, err := tree.FromPreorder(pre); err != nil || !tree.Compare(bt, rebuilt) {
+		t.Errorf("(%v != nil) or rebuilt tree differs", err)
+	}
+}
@@ -0,0 +1,122 @@
+package tree
+
+import (
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/stack"
+)
+
+// nodePair pairs a node of the tree being copied with its copy.
+type nodePair struct {
+	src, dst *Tnode
+}
+
+// copyTree returns a copy of the tree rooted at tn with data copied by copier, or shared if copier
+// is nil. If mirror is true, the left and right children of every node are swapped in the copy.
+func copyTree(tn *Tnode, copier container.Copier, mirror bool) *Tnode {
+	if tn == nil {
+		return nil
+	}
+
+	copyData := func(data interface{}) interface{} {
+		if copier == nil {
+			return data
+		}
+		return copier(data.(container.Interface))
+	}
+
+	root := &Tnode{data: copyData(tn.data), priority: tn.priority}
+	ls := stack.NewLStack()
+	ls.Push(nodePair{tn, root})
+
+	for !ls.Empty() {
+		np := ls.Pop().(nodePair)
+		left, right := np.src.lightChild, np.src.rightChild
+		if mirror {
+			left, right = right, left
+		}
+
+		if left != nil {
+			np.dst.lightChild = &Tnode{parent: np.dst, data: copyData(left.data), priority: left.priority}
+			ls.Push(nodePair{left, np.dst.lightChild})
+		}
+		if right != nil {
+			np.dst.rightChild = &Tnode{parent: np.dst, data: copyData(right.data), priority: right.priority}
+			ls.Push(nodePair{right, np.dst.rightChild})
+		}
+	}
+
+	return root
+}
+
+// forkBalancer returns a balancer for a copy of bt, or nil if bt isn't balanced.
+func (bt *BSTree) forkBalancer() balancer {
+	if bt.balancer == nil {
+		return nil
+	}
+	return bt.balancer.fork()
+}
+
+// Clone returns a tree of the same shape as bt that shares no nodes with it. The data are copied
+// by copier, or shared between both trees if copier is nil, in which case Update on either tree
+// is seen by the other. The clone is balanced the same way, uses the same Monoid and reports to
+// the same checker as bt.
+func (bt *BSTree) Clone(copier container.Copier) *BSTree {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	clone := &BSTree{
+		root:     copyTree(bt.root, copier, false),
+		size:     bt.size,
+		inverted: bt.inverted,
+		monoid:   bt.monoid,
+		balancer: bt.forkBalancer(),
+		checker:  bt.checker,
+	}
+	clone.fixAll(clone.root)
+	return clone
+}
+
+// Mirror returns a copy of bt with the left and right children of every node swapped, so that it
+// keeps its data in reverse order: InorderTrav yields the largest data first, PopMin removes the
+// largest data and DeleteRange takes its bounds in reverse. The data are shared with bt.
+func (bt *BSTree) Mirror() *BSTree {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	mirror := &BSTree{
+		root:     copyTree(bt.root, nil, true),
+		size:     bt.size,
+		inverted: !bt.inverted,
+		monoid:   bt.monoid,
+		balancer: bt.forkBalancer(),
+		checker:  bt.checker,
+	}
+	mirror.fixAll(mirror.root)
+	return mirror
+}
+
+// Invert swaps the left and right children of every node of bt in place, reversing the order in
+// which it keeps its data the same way as Mirror does. Inverting twice restores the tree.
+func (bt *BSTree) Invert() {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if bt.root != nil {
+		ls := stack.NewLStack()
+		ls.Push(bt.root)
+		for !ls.Empty() {
+			tn := ls.Pop().(*Tnode)
+			tn.lightChild, tn.rightChild = tn.rightChild, tn.lightChild
+			if tn.lightChild != nil {
+				ls.Push(tn.lightChild)
+			}
+			if tn.rightChild != nil {
+				ls.Push(tn.rightChild)
+			}
+		}
+	}
+	// Combine needn't be commutative, so aggregates are recomputed in the new order.
+	bt.fixAll(bt.root)
+	bt.inverted = !bt.inverted
+	bt.mods++
+}
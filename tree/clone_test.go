@@ -0,0 +1,119 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+func TestBSTreeClone(t *testing.T) {
+	bt, _ := createTree(index[1])
+	shallow := bt.Clone(nil)
+	deep := bt.Clone(func(data container.Interface) container.Interface {
+		c := *data.(*testdata.Corp)
+		return &c
+	})
+
+	for _, clone := range []*tree.BSTree{shallow, deep} {
+		if !tree.Compare(bt, clone) || clone.Size() != bt.Size() {
+			t.Errorf("clone differs from the tree")
+		}
+		if err := clone.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+
+	// Structural changes to the original don't reach the clones.
+	bt.Delete(5)
+	bt.Insert(&testdata.Corp{ID: 11})
+	if _, err := deep.Search(5); err != nil || deep.Size() != len(testCase) {
+		t.Errorf("(%v != nil) or (%v != %v)", err, deep.Size(), len(testCase))
+	}
+	if _, err := shallow.Search(11); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+
+	// Payloads are shared only with the shallow clone.
+	saved := testCase[1].Name
+	defer func() { testCase[1].Name = saved }()
+	bt.Update(3, "changed")
+	if itf, _ := shallow.Search(3); itf.(*testdata.Corp).Name != "changed" {
+		t.Errorf("%v != changed", itf)
+	}
+	if itf, _ := deep.Search(3); itf.(*testdata.Corp).Name != saved || itf == &testCase[1] {
+		t.Errorf("%v != %v or data is shared", itf, saved)
+	}
+
+	if clone := tree.NewBSTree().Clone(nil); !clone.Empty() {
+		t.Errorf("clone of empty tree is not empty")
+	}
+}
+
+func TestBSTreeMirror(t *testing.T) {
+	bt, _ := createTree(index[1])
+	mirror := bt.Mirror()
+
+	want := []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0}
+	if got := inorderIDs(mirror); !compareIntSlice(got, want) {
+		t.Errorf("%v != %v", got, want)
+	}
+	if got := inorderIDs(bt); got[0] != 0 {
+		t.Errorf("%v != 0", got[0])
+	}
+	if err := mirror.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	// The mirror is a working tree in reverse order.
+	for _, id := range []int{11, -1} {
+		if err := mirror.Insert(&testdata.Corp{ID: id}); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+	if itf, err := mirror.Search(4); err != nil || itf != &testCase[7] {
+		t.Errorf("(%v != nil) or (%v != %v)", err, itf, &testCase[7])
+	}
+	if itf, _ := mirror.PopMin(); itf.(*testdata.Corp).ID != 11 {
+		t.Errorf("%v != 11", itf)
+	}
+	if n := mirror.DeleteRange(8, 2); n != 7 {
+		t.Errorf("%v != 7", n)
+	}
+	want = []int{10, 9, 1, 0, -1}
+	if got := inorderIDs(mirror); !compareIntSlice(got, want) {
+		t.Errorf("%v != %v", got, want)
+	}
+	if err := mirror.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestBSTreeInvert(t *testing.T) {
+	bt, _ := createTree(r.Perm(len(testCase)))
+	levels := bt.Levels()
+
+	bt.Invert()
+	if got := inorderIDs(bt); got[0] != 10 || got[len(got)-1] != 0 {
+		t.Errorf("%v is not in reverse order", got)
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	for d, level := range bt.Levels() {
+		for i, itf := range level {
+			if itf != levels[d][len(level)-1-i] {
+				t.Errorf("%v != %v", itf, levels[d][len(level)-1-i])
+			}
+		}
+	}
+
+	bt.Invert()
+	if !tree.Compare(bt, bt.Clone(nil)) {
+		t.Errorf("tree differs from its clone")
+	}
+	if got := inorderIDs(bt); !compareIntSlice(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("%v is not in order", got)
+	}
+}
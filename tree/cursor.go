@@ -0,0 +1,164 @@
+package tree
+
+import "github.com/NzKSO/container"
+
+// Cursor points at a node of a BSTree and steps through the tree in inorder using parent
+// pointers, so that walking the whole tree with Next or Prev takes amortized O(1) per step.
+// A cursor that doesn't point at any node is invalid, which is the case after it steps past
+// either end of the tree, or when the tree was modified other than through the cursor since
+// the cursor was positioned.
+type Cursor struct {
+	bt   *BSTree
+	tn   *Tnode
+	mods uint64
+	err  error
+}
+
+// NewCursor returns an invalid cursor on bt, use First, Last or Seek to position it.
+func (bt *BSTree) NewCursor() *Cursor {
+	return &Cursor{bt: bt}
+}
+
+// Valid reports whether the cursor points at a node.
+func (c *Cursor) Valid() bool {
+	return c.tn != nil
+}
+
+// Node returns the node the cursor points at, or nil if the cursor is invalid.
+func (c *Cursor) Node() *Tnode {
+	return c.tn
+}
+
+// Err returns ErrConcurrentModification if the last step of the cursor failed because the
+// tree was modified, otherwise nil. Positioning the cursor with First, Last or Seek clears it.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// reset prepares the cursor for being positioned again.
+func (c *Cursor) reset() {
+	c.tn, c.err = nil, nil
+	c.mods = c.bt.mods
+}
+
+// stale invalidates the cursor if the tree was modified since it was positioned.
+func (c *Cursor) stale() bool {
+	if c.tn != nil && c.bt.mods != c.mods {
+		c.tn, c.err = nil, container.ErrConcurrentModification
+	}
+	return c.err != nil
+}
+
+// Data returns the data the cursor points at, or nil if the cursor is invalid.
+func (c *Cursor) Data() interface{} {
+	if c.tn == nil {
+		return nil
+	}
+	return c.tn.data
+}
+
+// First moves the cursor to the smallest data in the tree, it returns false if the tree is empty.
+func (c *Cursor) First() bool {
+	c.bt.rw.RLock()
+	defer c.bt.rw.RUnlock()
+
+	c.reset()
+	if c.bt.root != nil {
+		c.tn, _ = findLeftMostNode(c.bt.root, nil)
+	}
+	return c.tn != nil
+}
+
+// Last moves the cursor to the largest data in the tree, it returns false if the tree is empty.
+func (c *Cursor) Last() bool {
+	c.bt.rw.RLock()
+	defer c.bt.rw.RUnlock()
+
+	c.reset()
+	if c.bt.root != nil {
+		c.tn, _ = findRightMostNode(c.bt.root, nil)
+	}
+	return c.tn != nil
+}
+
+// Seek moves the cursor to the data found by key, or if there is none, to the smallest
+// data that Less orders after key. It returns false if no such data exists.
+func (c *Cursor) Seek(key interface{}) bool {
+	c.bt.rw.RLock()
+	defer c.bt.rw.RUnlock()
+
+	c.reset()
+	c.tn = c.bt.lowerBound(key)
+	return c.tn != nil
+}
+
+// successor returns the node following tn in inorder, or nil if tn is the last one.
+func successor(tn *Tnode) *Tnode {
+	if tn.rightChild != nil {
+		tn, _ = findLeftMostNode(tn.rightChild, tn)
+		return tn
+	}
+
+	for tn.parent != nil && tn.parent.rightChild == tn {
+		tn = tn.parent
+	}
+	return tn.parent
+}
+
+// predecessor returns the node preceding tn in inorder, or nil if tn is the first one.
+func predecessor(tn *Tnode) *Tnode {
+	if tn.lightChild != nil {
+		tn, _ = findRightMostNode(tn.lightChild, tn)
+		return tn
+	}
+
+	for tn.parent != nil && tn.parent.lightChild == tn {
+		tn = tn.parent
+	}
+	return tn.parent
+}
+
+// Next moves the cursor to the next data in inorder. It returns false, leaving the cursor
+// invalid, if there is no next data, the cursor was invalid, or the tree was modified.
+func (c *Cursor) Next() bool {
+	c.bt.rw.RLock()
+	defer c.bt.rw.RUnlock()
+
+	if !c.stale() && c.tn != nil {
+		c.tn = successor(c.tn)
+	}
+	return c.tn != nil
+}
+
+// Prev moves the cursor to the previous data in inorder. It returns false, leaving the cursor
+// invalid, if there is no previous data, the cursor was invalid, or the tree was modified.
+func (c *Cursor) Prev() bool {
+	c.bt.rw.RLock()
+	defer c.bt.rw.RUnlock()
+
+	if !c.stale() && c.tn != nil {
+		c.tn = predecessor(c.tn)
+	}
+	return c.tn != nil
+}
+
+// Delete deletes the data the cursor points at from the tree and moves the cursor to the next
+// data, so that deleting while stepping forward with Next doesn't skip any. If the cursor is
+// invalid, returns ErrNotExist, or ErrConcurrentModification if the tree was modified.
+func (c *Cursor) Delete() error {
+	c.bt.rw.Lock()
+	defer c.bt.rw.Unlock()
+
+	if c.stale() {
+		return c.err
+	}
+	if c.tn == nil {
+		return container.ErrNotExist
+	}
+
+	next := successor(c.tn)
+	c.bt.unlink(c.tn)
+	c.tn = next
+	c.mods = c.bt.mods
+	return nil
+}
@@ -0,0 +1,145 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+func TestCursorNextPrev(t *testing.T) {
+	bt, _ := createTree(r.Perm(len(testCase)))
+	c := bt.NewCursor()
+	if c.Valid() || c.Data() != nil || c.Next() {
+		t.Errorf("new cursor is valid")
+	}
+
+	var id int
+	for ok := c.First(); ok; ok = c.Next() {
+		if c.Data().(*testdata.Corp).ID != id || c.Node().GetData() != c.Data() {
+			t.Errorf("%v != %v", c.Data(), id)
+		}
+		id++
+	}
+	if id != len(testCase) || c.Valid() {
+		t.Errorf("(%v != %v) or cursor is valid past the end", id, len(testCase))
+	}
+
+	for ok := c.Last(); ok; ok = c.Prev() {
+		id--
+		if c.Data().(*testdata.Corp).ID != id {
+			t.Errorf("%v != %v", c.Data(), id)
+		}
+	}
+	if id != 0 {
+		t.Errorf("%v != 0", id)
+	}
+
+	empty := tree.NewBSTree().NewCursor()
+	if empty.First() || empty.Last() || empty.Seek(0) {
+		t.Errorf("cursor on empty tree is valid")
+	}
+}
+
+func TestCursorSeek(t *testing.T) {
+	bt := tree.NewBSTree()
+	for _, id := range []int{10, 4, 16, 2, 8, 12} {
+		bt.Insert(&testdata.Corp{ID: id})
+	}
+
+	sliOfSeek := []struct {
+		key, id int
+		ok      bool
+	}{
+		{8, 8, true},
+		{9, 10, true},
+		{0, 2, true},
+		{13, 16, true},
+		{17, 0, false},
+	}
+	c := bt.NewCursor()
+	for _, v := range sliOfSeek {
+		ok := c.Seek(v.key)
+		if ok != v.ok || (ok && c.Data().(*testdata.Corp).ID != v.id) {
+			t.Errorf("Seek(%v): (%v != %v) or (%v != %v)", v.key, ok, v.ok, c.Data(), v.id)
+		}
+	}
+
+	// Paginate in steps of two starting after 4.
+	var page []int
+	for ok := c.Seek(5); ok && len(page) < 2; ok = c.Next() {
+		page = append(page, c.Data().(*testdata.Corp).ID)
+	}
+	if want := []int{8, 10}; !compareIntSlice(page, want) {
+		t.Errorf("%v != %v", page, want)
+	}
+}
+
+func TestCursorDelete(t *testing.T) {
+	bt, _ := createTree(r.Perm(len(testCase)))
+	c := bt.NewCursor()
+	if err := c.Delete(); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+
+	// Delete every odd ID while walking forward.
+	var kept []int
+	for ok := c.First(); ok; {
+		if c.Data().(*testdata.Corp).ID%2 == 1 {
+			if err := c.Delete(); err != nil {
+				t.Errorf("%v != nil", err)
+			}
+			ok = c.Valid()
+			continue
+		}
+		kept = append(kept, c.Data().(*testdata.Corp).ID)
+		ok = c.Next()
+	}
+
+	if want := []int{0, 2, 4, 6, 8, 10}; !compareIntSlice(kept, want) {
+		t.Errorf("%v != %v", kept, want)
+	}
+	if bt.Size() != len(kept) {
+		t.Errorf("%v != %v", bt.Size(), len(kept))
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestBSTreeDeleteKeepsNodes(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	// 7 has two children, its successor 8 must keep its node rather than its data being moved.
+	c := bt.NewCursor()
+	c.Seek(8)
+	node := c.Node()
+	if err := bt.Delete(7); err != nil {
+		t.Fatalf("%v != nil", err)
+	}
+	if node.GetData().(*testdata.Corp).ID != 8 || node.GetParent().GetData().(*testdata.Corp).ID != 5 {
+		t.Errorf("node of 8 changed to %v", node.GetData())
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestCursorConcurrentModification(t *testing.T) {
+	bt, _ := createTree(defidx)
+	c := bt.NewCursor()
+
+	c.First()
+	bt.Delete(5)
+	if c.Next() || c.Err() != container.ErrConcurrentModification {
+		t.Errorf("Next() succeeded or (%v != %v)", c.Err(), container.ErrConcurrentModification)
+	}
+	if err := c.Delete(); err != container.ErrConcurrentModification {
+		t.Errorf("%v != %v", err, container.ErrConcurrentModification)
+	}
+
+	if !c.Last() || c.Err() != nil || !c.Prev() {
+		t.Errorf("cursor is not usable after being positioned again: %v", c.Err())
+	}
+}
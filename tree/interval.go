@@ -0,0 +1,205 @@
+package tree
+
+import (
+	"math"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/stack"
+)
+
+// Interval represents the half-open range [Start, End).
+type Interval struct {
+	Start, End int
+}
+
+// Overlaps reports whether iv and other have any point in common.
+func (iv Interval) Overlaps(other Interval) bool {
+	return iv.Start < other.End && other.Start < iv.End
+}
+
+// IntervalEntry is an interval stored in an IntervalTree together with its value.
+type IntervalEntry struct {
+	Interval Interval
+	Value    interface{}
+}
+
+// intervalData is the data kept in the nodes of an IntervalTree, it's ordered by start and then
+// by end, and can be looked up by an Interval.
+type intervalData struct {
+	IntervalEntry
+}
+
+func (id *intervalData) interval(kv interface{}) Interval {
+	if v, ok := kv.(*intervalData); ok {
+		return v.Interval
+	}
+	return kv.(Interval)
+}
+
+// Less implements interface Lesser in package container.
+func (id *intervalData) Less(kv interface{}) bool {
+	iv := id.interval(kv)
+	return id.Interval.Start < iv.Start || id.Interval.Start == iv.Start && id.Interval.End <= iv.End
+}
+
+// Find implements interface Finder in package container.
+func (id *intervalData) Find(key interface{}) bool {
+	return id.Interval == id.interval(key)
+}
+
+// Set implements interface Setter in package container, it replaces the value of the interval.
+func (id *intervalData) Set(v interface{}) {
+	id.Value = v
+}
+
+// maxEnd keeps the largest end of the intervals in every subtree.
+type maxEnd struct{}
+
+func (maxEnd) Identity() interface{} {
+	return math.MinInt
+}
+
+func (maxEnd) Combine(a, b interface{}) interface{} {
+	if a.(int) < b.(int) {
+		return b
+	}
+	return a
+}
+
+func (maxEnd) FromValue(data interface{}) interface{} {
+	return data.(*intervalData).Interval.End
+}
+
+// IntervalTree stores intervals with a value each, and finds the ones overlapping a given interval
+// or containing a given point. It's a treap keyed by the start of the intervals, so its height is
+// O(log n) expected whatever the order of insertion, and every subtree keeps the largest end of its
+// intervals, so that subtrees which can't overlap are skipped. The same interval can only be stored once.
+type IntervalTree struct {
+	bt *BSTree
+}
+
+// NewIntervalTree returns an empty interval tree.
+func NewIntervalTree() *IntervalTree {
+	return &IntervalTree{NewTreap(WithMonoid(maxEnd{})).BSTree}
+}
+
+// Insert inserts iv with value. If iv is empty, returns ErrInvalidInterval. If iv is already in the
+// tree, returns ErrDataExists.
+func (it *IntervalTree) Insert(iv Interval, value interface{}) error {
+	if iv.Start >= iv.End {
+		return container.ErrInvalidInterval
+	}
+	return it.bt.Insert(&intervalData{IntervalEntry{iv, value}})
+}
+
+// Delete deletes iv from the tree. If the tree is empty, returns ErrEmptyTree, if iv is not in the
+// tree, returns ErrNotExist.
+func (it *IntervalTree) Delete(iv Interval) error {
+	return it.bt.Delete(iv)
+}
+
+// Search returns the value stored with iv. If the tree is empty, returns ErrEmptyTree, if iv is not
+// in the tree, returns ErrNotExist.
+func (it *IntervalTree) Search(iv Interval) (interface{}, error) {
+	itf, err := it.bt.Search(iv)
+	if err != nil {
+		return nil, err
+	}
+	return itf.(*intervalData).Value, nil
+}
+
+// overlapping returns the entries overlapping [start, end) ordered by their intervals.
+func (it *IntervalTree) overlapping(start, end int) []IntervalEntry {
+	it.bt.rw.RLock()
+	defer it.bt.rw.RUnlock()
+
+	var entries []IntervalEntry
+	q := Interval{start, end}
+	ls := stack.NewLStack()
+
+	// This is inorder traversal skipping subtrees that end before q starts, and stopping at the
+	// first interval that starts after q ends.
+	for tn := it.bt.root; tn != nil || !ls.Empty(); {
+		for ; tn != nil && tn.agg.(int) > start; tn = tn.lightChild {
+			ls.Push(tn)
+		}
+		if ls.Empty() {
+			break
+		}
+
+		tn = ls.Pop().(*Tnode)
+		id := tn.data.(*intervalData)
+		if id.Interval.Start >= end {
+			// So do the intervals following it.
+			break
+		}
+		if id.Interval.Overlaps(q) {
+			entries = append(entries, id.IntervalEntry)
+		}
+		tn = tn.rightChild
+	}
+	return entries
+}
+
+// Overlapping returns the entries whose intervals overlap q, ordered by their intervals. It takes
+// O(k·height) in the worst case for k entries found, as every entry may be reached from a different
+// subtree.
+func (it *IntervalTree) Overlapping(q Interval) []IntervalEntry {
+	if q.Start >= q.End {
+		return nil
+	}
+	return it.overlapping(q.Start, q.End)
+}
+
+// Containing returns the entries whose intervals contain point, ordered by their intervals.
+func (it *IntervalTree) Containing(point int) []IntervalEntry {
+	if point == math.MaxInt {
+		// No interval contains it, and point+1 would overflow.
+		return nil
+	}
+	return it.overlapping(point, point+1)
+}
+
+// AnyOverlap reports whether any interval in the tree overlaps q, which takes O(height).
+func (it *IntervalTree) AnyOverlap(q Interval) bool {
+	if q.Start >= q.End {
+		return false
+	}
+
+	it.bt.rw.RLock()
+	defer it.bt.rw.RUnlock()
+
+	// If the left subtree ends after q starts but doesn't overlap q, its interval ending last starts
+	// at or after the end of q, and so do all intervals on the right.
+	for tn := it.bt.root; tn != nil; {
+		if tn.data.(*intervalData).Interval.Overlaps(q) {
+			return true
+		}
+		if tn.lightChild != nil && tn.lightChild.agg.(int) > q.Start {
+			tn = tn.lightChild
+		} else {
+			tn = tn.rightChild
+		}
+	}
+	return false
+}
+
+// Size returns the number of intervals in the tree.
+func (it *IntervalTree) Size() int {
+	return it.bt.Size()
+}
+
+// Empty returns true if the tree has no interval, otherwise false.
+func (it *IntervalTree) Empty() bool {
+	return it.bt.Empty()
+}
+
+// Height returns the height of the tree, see BSTree.Height.
+func (it *IntervalTree) Height() int {
+	return it.bt.Height()
+}
+
+// Validate checks the invariants of the tree, see BSTree.Validate.
+func (it *IntervalTree) Validate() error {
+	return it.bt.Validate()
+}
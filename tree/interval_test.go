@@ -0,0 +1,153 @@
+package tree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/tree"
+)
+
+func intervalsOf(entries []tree.IntervalEntry) []tree.Interval {
+	var ivs []tree.Interval
+	for _, e := range entries {
+		ivs = append(ivs, e.Interval)
+	}
+	return ivs
+}
+
+func compareIntervals(s1, s2 []tree.Interval) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if s1[i] != s2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIntervalTree(t *testing.T) {
+	it := tree.NewIntervalTree()
+	reservations := []tree.Interval{{15, 20}, {10, 30}, {17, 19}, {5, 20}, {12, 15}, {30, 40}, {5, 8}}
+	for _, iv := range r.Perm(len(reservations)) {
+		if err := it.Insert(reservations[iv], iv); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+
+	if err := it.Insert(tree.Interval{5, 20}, 0); err != container.ErrDataExists {
+		t.Errorf("%v != %v", err, container.ErrDataExists)
+	}
+	if err := it.Insert(tree.Interval{5, 5}, 0); err != container.ErrInvalidInterval {
+		t.Errorf("%v != %v", err, container.ErrInvalidInterval)
+	}
+	if v, err := it.Search(tree.Interval{17, 19}); err != nil || v != 2 {
+		t.Errorf("(%v != nil) or (%v != 2)", err, v)
+	}
+
+	tests := []struct {
+		q    tree.Interval
+		want []tree.Interval
+	}{
+		{tree.Interval{0, 5}, nil},
+		{tree.Interval{0, 6}, []tree.Interval{{5, 8}, {5, 20}}},
+		{tree.Interval{8, 10}, []tree.Interval{{5, 20}}},
+		{tree.Interval{19, 31}, []tree.Interval{{5, 20}, {10, 30}, {15, 20}, {30, 40}}},
+		{tree.Interval{40, 50}, nil},
+		{tree.Interval{-100, 100}, []tree.Interval{{5, 8}, {5, 20}, {10, 30}, {12, 15}, {15, 20}, {17, 19}, {30, 40}}},
+		{tree.Interval{20, 10}, nil},
+	}
+	for _, test := range tests {
+		got := intervalsOf(it.Overlapping(test.q))
+		if !compareIntervals(got, test.want) {
+			t.Errorf("Overlapping(%v): %v != %v", test.q, got, test.want)
+		}
+		if found := it.AnyOverlap(test.q); found != (len(test.want) > 0) {
+			t.Errorf("AnyOverlap(%v): %v != %v", test.q, found, len(test.want) > 0)
+		}
+	}
+
+	if got, want := intervalsOf(it.Containing(15)), []tree.Interval{{5, 20}, {10, 30}, {15, 20}}; !compareIntervals(got, want) {
+		t.Errorf("%v != %v", got, want)
+	}
+	if got := it.Containing(40); got != nil {
+		t.Errorf("%v != nil", got)
+	}
+	if got := it.Containing(math.MaxInt); got != nil {
+		t.Errorf("%v != nil", got)
+	}
+
+	if err := it.Delete(tree.Interval{5, 20}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if err := it.Delete(tree.Interval{5, 20}); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if got, want := intervalsOf(it.Overlapping(tree.Interval{8, 11})), []tree.Interval{{10, 30}}; !compareIntervals(got, want) {
+		t.Errorf("%v != %v", got, want)
+	}
+	if it.Size() != len(reservations)-1 {
+		t.Errorf("%v != %v", it.Size(), len(reservations)-1)
+	}
+	if err := it.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestIntervalTreeRandom(t *testing.T) {
+	it := tree.NewIntervalTree()
+	model := make(map[tree.Interval]bool)
+	for i := 0; i < 3000; i++ {
+		start := r.Intn(500)
+		iv := tree.Interval{start, start + 1 + r.Intn(50)}
+		if r.Intn(3) == 0 {
+			it.Delete(iv)
+			delete(model, iv)
+		} else {
+			it.Insert(iv, nil)
+			model[iv] = true
+		}
+
+		start = r.Intn(560) - 10
+		q := tree.Interval{start, start + 1 + r.Intn(30)}
+		var want int
+		for iv := range model {
+			if iv.Overlaps(q) {
+				want++
+			}
+		}
+		got := it.Overlapping(q)
+		if len(got) != want || it.AnyOverlap(q) != (want > 0) {
+			t.Fatalf("Overlapping(%v): %v != %v", q, len(got), want)
+		}
+		for j := range got {
+			if !got[j].Interval.Overlaps(q) || j > 0 && got[j].Interval.Start < got[j-1].Interval.Start {
+				t.Fatalf("%v is not ordered or doesn't overlap %v", intervalsOf(got), q)
+			}
+		}
+	}
+
+	if err := it.Validate(); err != nil || it.Size() != len(model) {
+		t.Errorf("(%v != nil) or (%v != %v)", err, it.Size(), len(model))
+	}
+}
+
+func TestIntervalTreeSortedInsertion(t *testing.T) {
+	it := tree.NewIntervalTree()
+	for start := 0; start < 10000; start++ {
+		it.Insert(tree.Interval{start, start + 10}, nil)
+	}
+	// Chronological intervals come sorted, which would make a plain tree a chain.
+	if h := it.Height(); h > 60 {
+		t.Errorf("height %v of 10000 intervals", h)
+	}
+
+	if got := it.Overlapping(tree.Interval{5000, 5005}); len(got) != 14 {
+		t.Errorf("%v != 14", len(got))
+	}
+	if err := it.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
@@ -0,0 +1,183 @@
+package tree
+
+import (
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/queue"
+)
+
+// eachLevel walks the tree breadth first like levelTraversal and calls visit with the nodes
+// of every level from left to right, starting at the root.
+func eachLevel(tn *Tnode, visit func(level []*Tnode)) {
+	if tn == nil {
+		return
+	}
+
+	lq := queue.NewLQueue()
+	lq.EnQueue(tn)
+
+	for !lq.Empty() {
+		level := make([]*Tnode, lq.Size())
+		for i := range level {
+			ret := lq.LeQueue().(*Tnode)
+			level[i] = ret
+			if ret.lightChild != nil {
+				lq.EnQueue(ret.lightChild)
+			}
+			if ret.rightChild != nil {
+				lq.EnQueue(ret.rightChild)
+			}
+		}
+		visit(level)
+	}
+}
+
+func dataOf(level []*Tnode) []interface{} {
+	ret := make([]interface{}, len(level))
+	for i, tn := range level {
+		ret[i] = tn.data
+	}
+	return ret
+}
+
+// Levels returns the data of the tree grouped by depth, Levels()[d] holding the data of
+// depth d from left to right. It returns nil if the tree is empty.
+func (bt *BSTree) Levels() [][]interface{} {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	var levels [][]interface{}
+	eachLevel(bt.root, func(level []*Tnode) {
+		levels = append(levels, dataOf(level))
+	})
+	return levels
+}
+
+// ZigzagLevels returns the data of the tree grouped by depth like Levels, but in spiral
+// order: the root level goes from left to right, the next one from right to left, and so on.
+func (bt *BSTree) ZigzagLevels() [][]interface{} {
+	levels := bt.Levels()
+	for d := 1; d < len(levels); d += 2 {
+		level := levels[d]
+		for l, r := 0, len(level)-1; l < r; l, r = l+1, r-1 {
+			level[l], level[r] = level[r], level[l]
+		}
+	}
+	return levels
+}
+
+// Width returns the maximum number of nodes on any level of the tree, or 0 if the tree is empty.
+func (bt *BSTree) Width() int {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	var width int
+	eachLevel(bt.root, func(level []*Tnode) {
+		if len(level) > width {
+			width = len(level)
+		}
+	})
+	return width
+}
+
+// LeafCount returns the number of nodes that have no child.
+func (bt *BSTree) LeafCount() int {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	var count int
+	eachLevel(bt.root, func(level []*Tnode) {
+		for _, tn := range level {
+			if tn.lightChild == nil && tn.rightChild == nil {
+				count++
+			}
+		}
+	})
+	return count
+}
+
+// LevelOf returns the level of data found by key, the root being on level 0, so that the data
+// is in Levels()[LevelOf(key)]. If the tree is empty, returns -1 and ErrEmptyTree. If not found,
+// returns -1 and ErrNotExist.
+func (bt *BSTree) LevelOf(key interface{}) (int, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil && bt.size == 0 {
+		return -1, container.ErrEmptyTree
+	}
+
+	return bt.getDepth(key)
+}
+
+// LeftView returns the data of the leftmost node of every level, starting at the root.
+func (bt *BSTree) LeftView() []interface{} {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	var view []interface{}
+	eachLevel(bt.root, func(level []*Tnode) {
+		view = append(view, level[0].data)
+	})
+	return view
+}
+
+// RightView returns the data of the rightmost node of every level, starting at the root.
+func (bt *BSTree) RightView() []interface{} {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	var view []interface{}
+	eachLevel(bt.root, func(level []*Tnode) {
+		view = append(view, level[len(level)-1].data)
+	})
+	return view
+}
+
+// offsetNode pairs a node with its horizontal offset from the root, left children being one
+// less than their parent and right children one more.
+type offsetNode struct {
+	tn     *Tnode
+	offset int
+}
+
+// TopView returns the data of the nodes seen when looking at the tree from above, that is
+// the shallowest node at every horizontal offset from the root, ordered from left to right.
+func (bt *BSTree) TopView() []interface{} {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil {
+		return nil
+	}
+
+	top := make(map[int]interface{})
+	minOffset, maxOffset := 0, 0
+
+	lq := queue.NewLQueue()
+	lq.EnQueue(offsetNode{bt.root, 0})
+
+	for !lq.Empty() {
+		ret := lq.LeQueue().(offsetNode)
+		if _, ok := top[ret.offset]; !ok {
+			top[ret.offset] = ret.tn.data
+			if ret.offset < minOffset {
+				minOffset = ret.offset
+			}
+			if ret.offset > maxOffset {
+				maxOffset = ret.offset
+			}
+		}
+		if ret.tn.lightChild != nil {
+			lq.EnQueue(offsetNode{ret.tn.lightChild, ret.offset - 1})
+		}
+		if ret.tn.rightChild != nil {
+			lq.EnQueue(offsetNode{ret.tn.rightChild, ret.offset + 1})
+		}
+	}
+
+	view := make([]interface{}, 0, len(top))
+	for offset := minOffset; offset <= maxOffset; offset++ {
+		view = append(view, top[offset])
+	}
+	return view
+}
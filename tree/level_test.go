@@ -0,0 +1,93 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/tree"
+)
+
+func TestBSTreeLevels(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	levels := [][]int{{5}, {3, 7}, {0, 4, 6, 9}, {2, 8, 10}, {1}}
+	got := bt.Levels()
+	if len(got) != len(levels) {
+		t.Fatalf("%v != %v", len(got), len(levels))
+	}
+	for d := range levels {
+		if !compareIntSlice(ids(t, got[d]), levels[d]) {
+			t.Errorf("%v != %v", ids(t, got[d]), levels[d])
+		}
+	}
+
+	zigzag := [][]int{{5}, {7, 3}, {0, 4, 6, 9}, {10, 8, 2}, {1}}
+	got = bt.ZigzagLevels()
+	for d := range zigzag {
+		if !compareIntSlice(ids(t, got[d]), zigzag[d]) {
+			t.Errorf("%v != %v", ids(t, got[d]), zigzag[d])
+		}
+	}
+
+	if levels := tree.NewBSTree().Levels(); levels != nil {
+		t.Errorf("%v != nil", levels)
+	}
+}
+
+func TestBSTreeWidth(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	if w := bt.Width(); w != 4 {
+		t.Errorf("%v != 4", w)
+	}
+	if n := bt.LeafCount(); n != 5 {
+		t.Errorf("%v != 5", n)
+	}
+
+	empty := tree.NewBSTree()
+	if empty.Width() != 0 || empty.LeafCount() != 0 {
+		t.Errorf("(%v != 0) or (%v != 0)", empty.Width(), empty.LeafCount())
+	}
+}
+
+func TestBSTreeLevelOf(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	for d, level := range bt.Levels() {
+		for _, id := range ids(t, level) {
+			if l, err := bt.LevelOf(id); err != nil || l != d {
+				t.Errorf("(%v != nil) or (%v != %v)", err, l, d)
+			}
+		}
+	}
+
+	if l, err := bt.LevelOf(11); l != -1 || err != container.ErrNotExist {
+		t.Errorf("(%v != -1) or (%v != %v)", l, err, container.ErrNotExist)
+	}
+	if l, err := tree.NewBSTree().LevelOf(1); l != -1 || err != container.ErrEmptyTree {
+		t.Errorf("(%v != -1) or (%v != %v)", l, err, container.ErrEmptyTree)
+	}
+}
+
+func TestBSTreeViews(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	views := []struct {
+		view func() []interface{}
+		ids  []int
+	}{
+		{bt.LeftView, []int{5, 3, 0, 2, 1}},
+		{bt.RightView, []int{5, 7, 9, 10, 1}},
+		// 1, 2, 4, 6 and 8 are hidden by shallower nodes at the same offset.
+		{bt.TopView, []int{0, 3, 5, 7, 9, 10}},
+	}
+	for _, v := range views {
+		if got := ids(t, v.view()); !compareIntSlice(got, v.ids) {
+			t.Errorf("%v != %v", got, v.ids)
+		}
+	}
+
+	if view := tree.NewBSTree().TopView(); view != nil {
+		t.Errorf("%v != nil", view)
+	}
+}
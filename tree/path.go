@@ -0,0 +1,156 @@
+package tree
+
+import "github.com/NzKSO/container"
+
+// pathTo walks down from the root the same way lookup does and returns the nodes on the
+// path from the root to the node found by key, or nil if the key doesn't exist.
+func (bt *BSTree) pathTo(key interface{}) []*Tnode {
+	var path []*Tnode
+
+	for walk := bt.root; walk != nil; {
+		path = append(path, walk)
+
+		v := walk.data.(container.Interface)
+		bt.observe(v, key)
+		if v.Find(key) {
+			return path
+		}
+
+		if bt.goesRight(v, key) {
+			walk = walk.rightChild
+		} else {
+			walk = walk.lightChild
+		}
+	}
+
+	return nil
+}
+
+// PathTo returns the data on the path from the root to the node found by key, with the root
+// first and the found data last. If the tree is empty, returns ErrEmptyTree. If not found,
+// returns ErrNotExist.
+func (bt *BSTree) PathTo(key interface{}) ([]interface{}, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil && bt.size == 0 {
+		return nil, container.ErrEmptyTree
+	}
+
+	path := bt.pathTo(key)
+	if path == nil {
+		return nil, container.ErrNotExist
+	}
+
+	ret := make([]interface{}, len(path))
+	for i, tn := range path {
+		ret[i] = tn.data
+	}
+	return ret, nil
+}
+
+// commonPrefix returns the length of the common prefix of both paths, both of which start at the root.
+func commonPrefix(p1, p2 []*Tnode) int {
+	var i int
+	for i < len(p1) && i < len(p2) && p1[i] == p2[i] {
+		i++
+	}
+	return i
+}
+
+// pathsOf returns the paths to the nodes found by k1 and k2.
+func (bt *BSTree) pathsOf(k1, k2 interface{}) ([]*Tnode, []*Tnode, error) {
+	if bt.root == nil && bt.size == 0 {
+		return nil, nil, container.ErrEmptyTree
+	}
+
+	p1 := bt.pathTo(k1)
+	if p1 == nil {
+		return nil, nil, container.ErrNotExist
+	}
+	p2 := bt.pathTo(k2)
+	if p2 == nil {
+		return nil, nil, container.ErrNotExist
+	}
+	return p1, p2, nil
+}
+
+// LowestCommonAncestor returns the data of the deepest node that has both the nodes found by
+// k1 and k2 as descendants, where a node is a descendant of itself. If the tree is empty,
+// returns ErrEmptyTree. If either key is not found, returns ErrNotExist.
+func (bt *BSTree) LowestCommonAncestor(k1, k2 interface{}) (interface{}, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	p1, p2, err := bt.pathsOf(k1, k2)
+	if err != nil {
+		return nil, err
+	}
+
+	return p1[commonPrefix(p1, p2)-1].data, nil
+}
+
+// Distance returns the number of edges on the path between the nodes found by k1 and k2.
+// If the tree is empty, returns -1 and ErrEmptyTree. If either key is not found, returns
+// -1 and ErrNotExist.
+func (bt *BSTree) Distance(k1, k2 interface{}) (int, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	p1, p2, err := bt.pathsOf(k1, k2)
+	if err != nil {
+		return -1, err
+	}
+
+	n := commonPrefix(p1, p2)
+	return len(p1) + len(p2) - 2*n, nil
+}
+
+// KthAncestor returns the data of the ancestor k edges above the node found by key, so k = 1
+// gives its parent and k = 0 the data itself. If the tree is empty, returns ErrEmptyTree. If
+// the key is not found, or the node has no ancestor k edges above it, returns ErrNotExist.
+func (bt *BSTree) KthAncestor(key interface{}, k int) (interface{}, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil && bt.size == 0 {
+		return nil, container.ErrEmptyTree
+	}
+
+	path := bt.pathTo(key)
+	if path == nil || k < 0 || k >= len(path) {
+		return nil, container.ErrNotExist
+	}
+
+	return path[len(path)-1-k].data, nil
+}
+
+// Siblings returns the data of the other children of the parent of the node found by key,
+// which in a binary tree is at most one. The root has no siblings. If the tree is empty,
+// returns ErrEmptyTree. If not found, returns ErrNotExist.
+func (bt *BSTree) Siblings(key interface{}) ([]interface{}, error) {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil && bt.size == 0 {
+		return nil, container.ErrEmptyTree
+	}
+
+	path := bt.pathTo(key)
+	if path == nil {
+		return nil, container.ErrNotExist
+	}
+	if len(path) == 1 {
+		return nil, nil
+	}
+
+	find, parent := path[len(path)-1], path[len(path)-2]
+	sibling := parent.lightChild
+	if sibling == find {
+		sibling = parent.rightChild
+	}
+	if sibling == nil {
+		return nil, nil
+	}
+	return []interface{}{sibling.data}, nil
+}
@@ -0,0 +1,137 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+// The tree built from defidx looks like:
+//
+//	      5
+//	   /     \
+//	  3       7
+//	 / \     / \
+//	0   4   6   9
+//	 \         / \
+//	  2       8   10
+//	 /
+//	1
+var defidx = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+func ids(t *testing.T, values []interface{}) []int {
+	ret := make([]int, len(values))
+	for i, v := range values {
+		pn, ok := v.(*testdata.Corp)
+		if !ok {
+			t.Fatalf("ok is %v", ok)
+		}
+		ret[i] = pn.ID
+	}
+	return ret
+}
+
+func TestBSTreePathTo(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	sliOfPath := []struct {
+		key  int
+		path []int
+	}{
+		{5, []int{5}},
+		{1, []int{5, 3, 0, 2, 1}},
+		{4, []int{5, 3, 4}},
+		{8, []int{5, 7, 9, 8}},
+	}
+	for _, v := range sliOfPath {
+		path, err := bt.PathTo(v.key)
+		if err != nil || !compareIntSlice(ids(t, path), v.path) {
+			t.Errorf("(%v != nil) or (%v != %v)", err, ids(t, path), v.path)
+		}
+	}
+
+	if _, err := bt.PathTo(11); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if _, err := tree.NewBSTree().PathTo(1); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+}
+
+func TestBSTreeLowestCommonAncestor(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	sliOfLCA := []struct {
+		k1, k2, lca, distance int
+	}{
+		{1, 4, 3, 4},
+		{1, 8, 5, 7},
+		{6, 10, 7, 3},
+		{2, 1, 2, 1},
+		{5, 5, 5, 0},
+		{9, 7, 7, 1},
+	}
+	for _, v := range sliOfLCA {
+		lca, err := bt.LowestCommonAncestor(v.k1, v.k2)
+		if err != nil || lca.(*testdata.Corp).ID != v.lca {
+			t.Errorf("(%v != nil) or (%v != %v)", err, lca, v.lca)
+		}
+
+		d, err := bt.Distance(v.k1, v.k2)
+		if err != nil || d != v.distance {
+			t.Errorf("(%v != nil) or (%v != %v)", err, d, v.distance)
+		}
+	}
+
+	if _, err := bt.LowestCommonAncestor(1, 11); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if d, err := bt.Distance(11, 1); d != -1 || err != container.ErrNotExist {
+		t.Errorf("(%v != -1) or (%v != %v)", d, err, container.ErrNotExist)
+	}
+}
+
+func TestBSTreeKthAncestor(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	for k, id := range []int{1, 2, 0, 3, 5} {
+		v, err := bt.KthAncestor(1, k)
+		if err != nil || v.(*testdata.Corp).ID != id {
+			t.Errorf("(%v != nil) or (%v != %v)", err, v, id)
+		}
+	}
+
+	if _, err := bt.KthAncestor(1, 5); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if _, err := bt.KthAncestor(1, -1); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+}
+
+func TestBSTreeSiblings(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	sliOfSiblings := []struct {
+		key      int
+		siblings []int
+	}{
+		{5, []int{}},
+		{3, []int{7}},
+		{9, []int{6}},
+		{2, []int{}},
+		{10, []int{8}},
+	}
+	for _, v := range sliOfSiblings {
+		siblings, err := bt.Siblings(v.key)
+		if err != nil || !compareIntSlice(ids(t, siblings), v.siblings) {
+			t.Errorf("(%v != nil) or (%v != %v)", err, siblings, v.siblings)
+		}
+	}
+
+	if _, err := bt.Siblings(11); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+}
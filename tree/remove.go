@@ -0,0 +1,76 @@
+package tree
+
+import "github.com/NzKSO/container"
+
+// PopMin removes the smallest data from the tree and returns it. If the tree is empty, returns ErrEmptyTree.
+func (bt *BSTree) PopMin() (interface{}, error) {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if bt.root == nil {
+		return nil, container.ErrEmptyTree
+	}
+
+	tn, _ := findLeftMostNode(bt.root, nil)
+	bt.unlink(tn)
+	return tn.data, nil
+}
+
+// PopMax removes the largest data from the tree and returns it. If the tree is empty, returns ErrEmptyTree.
+func (bt *BSTree) PopMax() (interface{}, error) {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if bt.root == nil {
+		return nil, container.ErrEmptyTree
+	}
+
+	tn, _ := findRightMostNode(bt.root, nil)
+	bt.unlink(tn)
+	return tn.data, nil
+}
+
+// DeleteRange deletes every data whose key lies between lo and hi inclusive, and returns the number
+// of data deleted. It walks the tree once, starting from the data found by lo or the first one after it.
+func (bt *BSTree) DeleteRange(lo, hi interface{}) int {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	var n int
+	for tn := bt.lowerBound(lo); tn != nil; n++ {
+		v := tn.data.(container.Interface)
+		bt.observe(v, hi)
+		if !v.Find(hi) && !bt.goesRight(v, hi) {
+			break
+		}
+
+		// Nodes are spliced rather than copied on unlink, so next stays in place.
+		next := successor(tn)
+		bt.unlink(tn)
+		tn = next
+	}
+	return n
+}
+
+// DeleteIf deletes every data for which pred returns true, and returns the number of data deleted.
+// pred is called once for each data in inorder, it must not access the tree.
+func (bt *BSTree) DeleteIf(pred func(data interface{}) bool) int {
+	bt.rw.Lock()
+	defer bt.rw.Unlock()
+
+	if bt.root == nil {
+		return 0
+	}
+
+	var n int
+	tn, _ := findLeftMostNode(bt.root, nil)
+	for tn != nil {
+		next := successor(tn)
+		if pred(tn.data) {
+			bt.unlink(tn)
+			n++
+		}
+		tn = next
+	}
+	return n
+}
@@ -0,0 +1,109 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+// inorderIDs returns IDs of the data in bt in inorder.
+func inorderIDs(bt *tree.BSTree) []int {
+	var got []int
+	for _, itf := range collect(bt, tree.InorderTrav) {
+		got = append(got, itf.(*testdata.Corp).ID)
+	}
+	return got
+}
+
+func TestBSTreePopMinMax(t *testing.T) {
+	bt, _ := createTree(r.Perm(len(testCase)))
+
+	for lo, hi := 0, len(testCase)-1; lo <= hi; lo, hi = lo+1, hi-1 {
+		itf, err := bt.PopMin()
+		if err != nil || itf.(*testdata.Corp).ID != lo {
+			t.Errorf("(%v != nil) or (%v != %v)", err, itf, lo)
+		}
+		if lo == hi {
+			break
+		}
+		itf, err = bt.PopMax()
+		if err != nil || itf.(*testdata.Corp).ID != hi {
+			t.Errorf("(%v != nil) or (%v != %v)", err, itf, hi)
+		}
+		if err = bt.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+
+	if !bt.Empty() || bt.Size() != 0 {
+		t.Errorf("tree is empty? %v, %v != 0", bt.Empty(), bt.Size())
+	}
+	if _, err := bt.PopMin(); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+	if _, err := bt.PopMax(); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+}
+
+func TestBSTreeDeleteRange(t *testing.T) {
+	tests := []struct {
+		lo, hi int
+		want   []int
+	}{
+		{3, 7, []int{0, 1, 2, 8, 9, 10}},
+		{-5, 2, []int{3, 4, 5, 6, 7, 8, 9, 10}},
+		{9, 20, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{4, 4, []int{0, 1, 2, 3, 5, 6, 7, 8, 9, 10}},
+		{7, 3, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{11, 15, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{-1, 10, nil},
+	}
+
+	for _, test := range tests {
+		bt, _ := createTree(r.Perm(len(testCase)))
+		n := bt.DeleteRange(test.lo, test.hi)
+		if got := inorderIDs(bt); !compareIntSlice(got, test.want) {
+			t.Errorf("DeleteRange(%v, %v): %v != %v", test.lo, test.hi, got, test.want)
+		}
+		if n != len(testCase)-len(test.want) || bt.Size() != len(test.want) {
+			t.Errorf("(%v != %v) or (%v != %v)", n, len(testCase)-len(test.want), bt.Size(), len(test.want))
+		}
+		if err := bt.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+
+	if n := tree.NewBSTree().DeleteRange(0, 10); n != 0 {
+		t.Errorf("%v != 0", n)
+	}
+}
+
+func TestBSTreeDeleteIf(t *testing.T) {
+	bt, _ := createTree(r.Perm(len(testCase)))
+
+	var seen []int
+	n := bt.DeleteIf(func(data interface{}) bool {
+		id := data.(*testdata.Corp).ID
+		seen = append(seen, id)
+		return id%2 == 1
+	})
+	if want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}; !compareIntSlice(seen, want) {
+		t.Errorf("%v != %v", seen, want)
+	}
+	if got, want := inorderIDs(bt), []int{0, 2, 4, 6, 8, 10}; n != 5 || !compareIntSlice(got, want) {
+		t.Errorf("(%v != 5) or (%v != %v)", n, got, want)
+	}
+	if err := bt.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+
+	if n = bt.DeleteIf(func(interface{}) bool { return true }); n != 6 || !bt.Empty() {
+		t.Errorf("(%v != 6) or tree is not empty", n)
+	}
+	if n = bt.DeleteIf(func(interface{}) bool { return true }); n != 0 {
+		t.Errorf("%v != 0", n)
+	}
+}
@@ -0,0 +1,103 @@
+package tree
+
+import "github.com/NzKSO/container"
+
+// splayBalancer moves the nodes next to every insertion and deletion to the root.
+type splayBalancer struct{}
+
+func (splayBalancer) linked(bt *BSTree, tn *Tnode) {
+	bt.splay(tn)
+}
+
+func (splayBalancer) unlinking(bt *BSTree, tn *Tnode) {}
+
+func (splayBalancer) unlinked(bt *BSTree, changed *Tnode) {
+	if changed != nil {
+		bt.splay(changed)
+	}
+}
+
+func (splayBalancer) check(tn *Tnode) error {
+	return nil
+}
+
+func (sb splayBalancer) fork() balancer {
+	return sb
+}
+
+// splay rotates tn up to the root, pairing the rotations so that the depth of the nodes on its
+// path is roughly halved.
+func (bt *BSTree) splay(tn *Tnode) {
+	for tn.parent != nil {
+		parent, grand := tn.parent, tn.parent.parent
+		switch {
+		case grand == nil:
+			bt.rotateUp(tn)
+		case (grand.lightChild == parent) == (parent.lightChild == tn):
+			bt.rotateUp(parent)
+			bt.rotateUp(tn)
+		default:
+			bt.rotateUp(tn)
+			bt.rotateUp(tn)
+		}
+	}
+}
+
+// SplayTree is a binary search tree that moves every node it searches, inserts or updates to the
+// root, and the parent of every node it deletes, so that data accessed often stay near the root.
+// Any sequence of m operations takes O(m log n) time. It has all methods of BSTree, but as Search
+// and Update restructure the tree, they lock it for writing and stop concurrent traversals with
+// ErrConcurrentModification.
+type SplayTree struct {
+	*BSTree
+}
+
+// NewSplayTree returns an empty splay tree configured by opts.
+func NewSplayTree(opts ...Option) *SplayTree {
+	bt := NewBSTree(opts...)
+	bt.balancer = splayBalancer{}
+	return &SplayTree{bt}
+}
+
+// Search searches the tree for the data found by key and moves it to the root. If the tree is empty,
+// returns ErrEmptyTree. If not found, returns ErrNotExist, having moved the last node visited to the
+// root, so that repeated misses restructure the tree as well.
+func (st *SplayTree) Search(key interface{}) (interface{}, error) {
+	st.rw.Lock()
+	defer st.rw.Unlock()
+
+	if st.root == nil {
+		return nil, container.ErrEmptyTree
+	}
+
+	tn, last := st.descend(key)
+	st.splay(last)
+	if tn == nil {
+		return nil, container.ErrNotExist
+	}
+	return tn.data, nil
+}
+
+// Update updates the data found by key like BSTree.Update does, and moves it to the root. If not
+// found, the last node visited is moved to the root, as Search does.
+func (st *SplayTree) Update(key interface{}, val interface{}) error {
+	st.rw.Lock()
+	defer st.rw.Unlock()
+
+	// If the key was changed, the data was moved to the root when it was inserted again.
+	tn, err := st.update(key, val)
+	if tn != nil {
+		st.splay(tn)
+	}
+	return err
+}
+
+// Clone returns a splay tree of the same shape as st that shares no nodes with it, see BSTree.Clone.
+func (st *SplayTree) Clone(copier container.Copier) *SplayTree {
+	return &SplayTree{st.BSTree.Clone(copier)}
+}
+
+// Mirror returns a copy of st keeping its data in reverse order, see BSTree.Mirror.
+func (st *SplayTree) Mirror() *SplayTree {
+	return &SplayTree{st.BSTree.Mirror()}
+}
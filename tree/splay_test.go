@@ -0,0 +1,141 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+func TestSplayTreeAccess(t *testing.T) {
+	st := tree.NewSplayTree()
+	if _, err := st.Search(0); err != container.ErrEmptyTree {
+		t.Errorf("%v != %v", err, container.ErrEmptyTree)
+	}
+
+	for _, iv := range r.Perm(len(testCase)) {
+		st.Insert(&testCase[iv])
+		if d, _ := st.DepthOf(testCase[iv].ID); d != 0 {
+			t.Errorf("inserted %v at depth %v", testCase[iv], d)
+		}
+	}
+
+	for _, iv := range r.Perm(len(testCase)) {
+		if itf, err := st.Search(testCase[iv].ID); err != nil || itf != &testCase[iv] {
+			t.Errorf("(%v != nil) or (%v != %v)", err, itf, &testCase[iv])
+		}
+		if d, _ := st.DepthOf(testCase[iv].ID); d != 0 {
+			t.Errorf("searched %v at depth %v", testCase[iv], d)
+		}
+	}
+	if _, err := st.Search(11); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+
+	saved := testCase[7].Name
+	defer func() { testCase[7].Name = saved }()
+	if err := st.Update(4, "Pear"); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if d, _ := st.DepthOf(4); d != 0 {
+		t.Errorf("updated 4 at depth %v", d)
+	}
+	if err := st.Update(11, "Pear"); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+
+	if err := st.Delete(4); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if err := st.Validate(); err != nil || st.Size() != len(testCase)-1 {
+		t.Errorf("(%v != nil) or (%v != %v)", err, st.Size(), len(testCase)-1)
+	}
+	if err := st.Clone(nil).Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestSplayTreeRandom(t *testing.T) {
+	st := tree.NewSplayTree(tree.WithMonoid(idSum{}))
+	model := make(map[int]bool)
+	for i := 0; i < 5000; i++ {
+		id := r.Intn(500)
+		switch r.Intn(4) {
+		case 0:
+			if err := st.Delete(id); (err == nil) != model[id] {
+				t.Fatalf("Delete(%v): %v", id, err)
+			}
+			delete(model, id)
+		case 1:
+			if _, err := st.Search(id); (err == nil) != model[id] {
+				t.Fatalf("Search(%v): %v", id, err)
+			}
+		default:
+			if err := st.Insert(&testdata.Corp{ID: id}); (err == nil) == model[id] {
+				t.Fatalf("Insert(%v): %v", id, err)
+			}
+			model[id] = true
+		}
+		if i%100 == 0 {
+			if err := st.Validate(); err != nil {
+				t.Fatalf("%v != nil", err)
+			}
+		}
+	}
+
+	var sum int
+	for id := range model {
+		sum += id
+	}
+	if agg, _ := st.Aggregate(0, 500); agg != sum || st.Size() != len(model) {
+		t.Errorf("(%v != %v) or (%v != %v)", agg, sum, st.Size(), len(model))
+	}
+	if n := st.DeleteIf(func(interface{}) bool { return true }); n != len(model) || !st.Empty() {
+		t.Errorf("%v != %v", n, len(model))
+	}
+}
+
+func TestSplayTreeSortedAccess(t *testing.T) {
+	st := tree.NewSplayTree()
+	for id := 0; id < 10000; id++ {
+		st.Insert(&testdata.Corp{ID: id})
+	}
+	// Sorted insertion makes a chain, which searching its far end folds up.
+	st.Search(0)
+	if h := st.Height(); h > 5001 {
+		t.Errorf("height %v after searching the deepest node", h)
+	}
+	if err := st.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestSplayTreeMisses(t *testing.T) {
+	st := tree.NewSplayTree()
+	for id := 0; id < 2000; id += 2 {
+		st.Insert(&testdata.Corp{ID: id})
+	}
+	if h := st.Height(); h != 999 {
+		t.Errorf("%v != 999", h)
+	}
+
+	// A miss below the chain splays its deepest node, which folds the chain up like a hit does.
+	if _, err := st.Search(-1); err != container.ErrNotExist {
+		t.Errorf("%v != %v", err, container.ErrNotExist)
+	}
+	if h := st.Height(); h > 501 {
+		t.Errorf("height %v after missing below the deepest node", h)
+	}
+	for i := 0; i < 1000; i++ {
+		if err := st.Update(2*r.Intn(1000)+1, "x"); err != container.ErrNotExist {
+			t.Errorf("%v != %v", err, container.ErrNotExist)
+		}
+	}
+	if h := st.Height(); h > 100 {
+		t.Errorf("height %v after random misses", h)
+	}
+	if err := st.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
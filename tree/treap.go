@@ -0,0 +1,194 @@
+package tree
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/NzKSO/container"
+)
+
+// WithSeed makes a Treap draw the priorities of its nodes from a random number generator seeded
+// with seed, so that inserting the same data in the same order always builds the same tree. It
+// has no effect on other trees.
+func WithSeed(seed int64) Option {
+	return func(bt *BSTree) {
+		bt.seed = &seed
+	}
+}
+
+// treapBalancer gives every node a random priority and keeps every parent's priority at least
+// that of its children, which makes the tree shaped as if its data were inserted in random order.
+type treapBalancer struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func newTreapBalancer(seed int64) *treapBalancer {
+	return &treapBalancer{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (tb *treapBalancer) priority() uint32 {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.rnd.Uint32()
+}
+
+func (tb *treapBalancer) linked(bt *BSTree, tn *Tnode) {
+	tn.priority = tb.priority()
+	for tn.parent != nil && tn.parent.priority < tn.priority {
+		bt.rotateUp(tn)
+	}
+}
+
+func (tb *treapBalancer) unlinking(bt *BSTree, tn *Tnode) {
+	// Rotate tn down below its child of higher priority until it has at most one child, so that
+	// unlinking it doesn't move any other node.
+	for tn.lightChild != nil && tn.rightChild != nil {
+		if tn.lightChild.priority > tn.rightChild.priority {
+			bt.rotateUp(tn.lightChild)
+		} else {
+			bt.rotateUp(tn.rightChild)
+		}
+	}
+}
+
+func (tb *treapBalancer) unlinked(bt *BSTree, changed *Tnode) {}
+
+func (tb *treapBalancer) check(tn *Tnode) error {
+	if tn.parent != nil && tn.parent.priority < tn.priority {
+		return fmt.Errorf("%w: priority of %v is above that of its parent %v", container.ErrCorrupted, tn.data, tn.parent.data)
+	}
+	return nil
+}
+
+func (tb *treapBalancer) fork() balancer {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return newTreapBalancer(tb.rnd.Int63())
+}
+
+// Treap is a binary search tree balanced by random priorities, whose expected height is O(log n)
+// whatever the order of insertion. It has all methods of BSTree, and can be split and merged by
+// key in O(log n).
+type Treap struct {
+	*BSTree
+}
+
+// NewTreap returns an empty treap configured by opts, see WithSeed for deterministic priorities.
+func NewTreap(opts ...Option) *Treap {
+	bt := NewBSTree(opts...)
+	seed := time.Now().UnixNano()
+	if bt.seed != nil {
+		seed = *bt.seed
+	}
+	bt.balancer = newTreapBalancer(seed)
+	return &Treap{bt}
+}
+
+// Clone returns a treap of the same shape as tr that shares no nodes with it, see BSTree.Clone.
+func (tr *Treap) Clone(copier container.Copier) *Treap {
+	return &Treap{tr.BSTree.Clone(copier)}
+}
+
+// Mirror returns a copy of tr keeping its data in reverse order, see BSTree.Mirror.
+func (tr *Treap) Mirror() *Treap {
+	return &Treap{tr.BSTree.Mirror()}
+}
+
+// Split moves the data of tr found by key or ordered after it to a new treap, which it returns,
+// leaving the data ordered before key in tr. The new treap is configured the same way as tr.
+func (tr *Treap) Split(key interface{}) *Treap {
+	tr.rw.Lock()
+	defer tr.rw.Unlock()
+
+	// Every node on the way down is appended to the right spine of the left tree or the left
+	// spine of the right tree, depending on which side of key it lies.
+	var left, right, leftParent, rightParent *Tnode
+	leftLink, rightLink := &left, &right
+	for tn := tr.root; tn != nil; {
+		v := tn.data.(container.Interface)
+		if !tr.atOrAfter(v, key) {
+			*leftLink, tn.parent, leftParent = tn, leftParent, tn
+			leftLink, tn = &tn.rightChild, tn.rightChild
+		} else {
+			*rightLink, tn.parent, rightParent = tn, rightParent, tn
+			rightLink, tn = &tn.lightChild, tn.lightChild
+		}
+	}
+	*leftLink, *rightLink = nil, nil
+	tr.fixUp(leftParent)
+	tr.fixUp(rightParent)
+
+	split := &BSTree{
+		root:     right,
+		size:     countOf(right),
+		inverted: tr.inverted,
+		monoid:   tr.monoid,
+		balancer: tr.balancer.fork(),
+		checker:  tr.checker,
+	}
+	tr.root, tr.size = left, countOf(left)
+	tr.mods++
+	return &Treap{split}
+}
+
+// Merge moves all data of other to tr, leaving other empty. Every data of tr must be ordered
+// before every data of other, otherwise Merge returns ErrOverlap and changes neither. Both treaps
+// must be configured the same way, as they are after Split. Traversals of other started before
+// Merge stop with ErrConcurrentModification.
+func (tr *Treap) Merge(other *Treap) error {
+	if tr == other {
+		return container.ErrOverlap
+	}
+
+	// Both treaps are locked in the order of their addresses, so that merges of the same treaps
+	// in opposite directions can't deadlock.
+	first, second := &tr.rw, &other.rw
+	if uintptr(unsafe.Pointer(second)) < uintptr(unsafe.Pointer(first)) {
+		first, second = second, first
+	}
+	first.Lock()
+	defer first.Unlock()
+	second.Lock()
+	defer second.Unlock()
+
+	if tr.root != nil && other.root != nil {
+		last, _ := findRightMostNode(tr.root, nil)
+		first, _ := findLeftMostNode(other.root, nil)
+		if v := last.data.(container.Interface); tr.atOrAfter(v, first.data) {
+			return container.ErrOverlap
+		}
+	}
+
+	// The node of higher priority of both roots becomes the root, and the remaining trees are
+	// merged below it the same way.
+	var parent *Tnode
+	a, b, link := tr.root, other.root, &tr.root
+	for a != nil && b != nil {
+		if a.priority >= b.priority {
+			*link, a.parent, parent = a, parent, a
+			link, a = &a.rightChild, a.rightChild
+		} else {
+			*link, b.parent, parent = b, parent, b
+			link, b = &b.lightChild, b.lightChild
+		}
+	}
+	rest := a
+	if rest == nil {
+		rest = b
+	}
+	*link = rest
+	if rest != nil {
+		rest.parent = parent
+	}
+	tr.fixUp(parent)
+
+	tr.size += other.size
+	tr.mods++
+	other.root, other.size = nil, 0
+	other.mods++
+	return nil
+}
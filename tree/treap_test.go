@@ -0,0 +1,178 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+// levelIDs returns IDs of the data in bt level by level.
+func levelIDs(bt *tree.BSTree) []int {
+	var got []int
+	for _, level := range bt.Levels() {
+		for _, itf := range level {
+			got = append(got, itf.(*testdata.Corp).ID)
+		}
+	}
+	return got
+}
+
+func TestTreapRandom(t *testing.T) {
+	tr := tree.NewTreap(tree.WithMonoid(idSum{}))
+	model := make(map[int]bool)
+	for i := 0; i < 5000; i++ {
+		id := r.Intn(500)
+		switch r.Intn(3) {
+		case 0:
+			if err := tr.Delete(id); (err == nil) != model[id] {
+				t.Fatalf("Delete(%v): %v", id, err)
+			}
+			delete(model, id)
+		default:
+			if err := tr.Insert(&testdata.Corp{ID: id}); (err == nil) == model[id] {
+				t.Fatalf("Insert(%v): %v", id, err)
+			}
+			model[id] = true
+		}
+		if i%100 == 0 {
+			if err := tr.Validate(); err != nil {
+				t.Fatalf("%v != nil", err)
+			}
+		}
+	}
+
+	var sum int
+	for id := range model {
+		sum += id
+	}
+	if agg, _ := tr.Aggregate(0, 500); agg != sum || tr.Size() != len(model) {
+		t.Errorf("(%v != %v) or (%v != %v)", agg, sum, tr.Size(), len(model))
+	}
+	if err := tr.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestTreapSortedInsertion(t *testing.T) {
+	tr := tree.NewTreap()
+	for id := 0; id < 10000; id++ {
+		tr.Insert(&testdata.Corp{ID: id})
+	}
+	// The expected height is about 3 log n, a plain tree would be a chain.
+	if h := tr.Height(); h > 60 {
+		t.Errorf("height %v of 10000 nodes", h)
+	}
+
+	n := tr.DeleteRange(1000, 8999)
+	if itf, err := tr.PopMin(); n != 8000 || err != nil || itf.(*testdata.Corp).ID != 0 {
+		t.Errorf("(%v != 8000) or (%v != nil) or (%v != 0)", n, err, itf)
+	}
+	if err := tr.Validate(); err != nil || tr.Size() != 1999 {
+		t.Errorf("(%v != nil) or (%v != 1999)", err, tr.Size())
+	}
+}
+
+func TestTreapSeed(t *testing.T) {
+	ri := r.Perm(200)
+	tr1 := tree.NewTreap(tree.WithSeed(42))
+	tr2 := tree.NewTreap(tree.WithSeed(42))
+	for _, id := range ri {
+		tr1.Insert(&testdata.Corp{ID: id})
+		tr2.Insert(&testdata.Corp{ID: id})
+	}
+	if !compareIntSlice(levelIDs(tr1.BSTree), levelIDs(tr2.BSTree)) {
+		t.Errorf("treaps of the same seed differ")
+	}
+
+	// The shape doesn't depend on the order of insertion either, only on the priorities, which
+	// are drawn in order of insertion.
+	clone := tr1.Clone(nil)
+	if err := clone.Validate(); err != nil || !tree.Compare(clone.BSTree, tr1.BSTree) {
+		t.Errorf("(%v != nil) or clone differs", err)
+	}
+	if err := tr1.Mirror().Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
+
+func TestTreapSplitMerge(t *testing.T) {
+	tr := tree.NewTreap(tree.WithMonoid(idSum{}))
+	for _, id := range r.Perm(100) {
+		tr.Insert(&testdata.Corp{ID: id})
+	}
+
+	right := tr.Split(40)
+	if tr.Size() != 40 || right.Size() != 60 {
+		t.Errorf("(%v != 40) or (%v != 60)", tr.Size(), right.Size())
+	}
+	for _, bt := range []*tree.BSTree{tr.BSTree, right.BSTree} {
+		if err := bt.Validate(); err != nil {
+			t.Errorf("%v != nil", err)
+		}
+	}
+	if got := inorderIDs(tr.BSTree); got[0] != 0 || got[len(got)-1] != 39 {
+		t.Errorf("%v is not 0 to 39", got)
+	}
+	if agg, _ := right.Aggregate(0, 100); agg != (40+99)*60/2 {
+		t.Errorf("%v != %v", agg, (40+99)*60/2)
+	}
+
+	if err := right.Merge(tr); err != container.ErrOverlap {
+		t.Errorf("%v != %v", err, container.ErrOverlap)
+	}
+	if err := tr.Merge(tr); err != container.ErrOverlap {
+		t.Errorf("%v != %v", err, container.ErrOverlap)
+	}
+	ch := right.Traversal(tree.InorderTrav)
+	<-ch
+	if err := tr.Merge(right); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	var last interface{}
+	for itf := range ch {
+		last = itf
+	}
+	if last != container.ErrConcurrentModification {
+		t.Errorf("%v != %v", last, container.ErrConcurrentModification)
+	}
+	if tr.Size() != 100 || !right.Empty() {
+		t.Errorf("(%v != 100) or split treap is not empty", tr.Size())
+	}
+	if err := tr.Validate(); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+	if agg, _ := tr.Aggregate(0, 100); agg != 99*100/2 {
+		t.Errorf("%v != %v", agg, 99*100/2)
+	}
+
+	// Splitting below or above all data leaves one side empty.
+	if all := tr.Split(-1); !tr.Empty() || all.Size() != 100 {
+		t.Errorf("(%v != 0) or (%v != 100)", tr.Size(), all.Size())
+	} else if none := all.Split(100); !none.Empty() || all.Validate() != nil {
+		t.Errorf("%v != 0", none.Size())
+	}
+}
+
+func TestTreapMergeOpposite(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		a, b := tree.NewTreap(), tree.NewTreap()
+		a.Insert(&testdata.Corp{ID: 0})
+		b.Insert(&testdata.Corp{ID: 1})
+
+		// Which merge goes first is up to the scheduler, but both return and one of the
+		// treaps ends up with all data.
+		errs := make(chan error, 2)
+		go func() { errs <- a.Merge(b) }()
+		go func() { errs <- b.Merge(a) }()
+		for _, err := range []error{<-errs, <-errs} {
+			if err != nil && err != container.ErrOverlap {
+				t.Errorf("%v != %v", err, container.ErrOverlap)
+			}
+		}
+		if a.Size()+b.Size() != 2 || (!a.Empty() && !b.Empty()) {
+			t.Errorf("%v and %v", a.Size(), b.Size())
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package tree
+
+import (
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/stack"
+)
+
+// WalkControl is returned by the hooks of a Visitor to tell Walk how to proceed.
+type WalkControl int
+
+// These constants respectively denote continuing the walk, skipping the remaining
+// subtrees of the current node, and stopping the walk.
+const (
+	Continue WalkControl = iota
+	SkipSubtree
+	Stop
+)
+
+// Visitor is used by Walk to visit every node of the tree. Pre is called before the left
+// subtree of a node is walked, In between its left and right subtree, and Post after its
+// right subtree.
+//
+// If Pre returns SkipSubtree, neither subtree of the node is walked; if In returns it, the
+// right subtree isn't. In both cases the remaining hooks of the node itself are still called,
+// so that every Pre is matched by a Post. SkipSubtree returned by Post is the same as Continue.
+// Stop ends the walk immediately.
+type Visitor interface {
+	Pre(tn *Tnode) WalkControl
+	In(tn *Tnode) WalkControl
+	Post(tn *Tnode) WalkControl
+}
+
+// VisitorFuncs implements Visitor using optional functions, a nil function meaning Continue.
+type VisitorFuncs struct {
+	PreFunc  func(tn *Tnode) WalkControl
+	InFunc   func(tn *Tnode) WalkControl
+	PostFunc func(tn *Tnode) WalkControl
+}
+
+// Pre calls vf.PreFunc if it is not nil.
+func (vf VisitorFuncs) Pre(tn *Tnode) WalkControl {
+	if vf.PreFunc == nil {
+		return Continue
+	}
+	return vf.PreFunc(tn)
+}
+
+// In calls vf.InFunc if it is not nil.
+func (vf VisitorFuncs) In(tn *Tnode) WalkControl {
+	if vf.InFunc == nil {
+		return Continue
+	}
+	return vf.InFunc(tn)
+}
+
+// Post calls vf.PostFunc if it is not nil.
+func (vf VisitorFuncs) Post(tn *Tnode) WalkControl {
+	if vf.PostFunc == nil {
+		return Continue
+	}
+	return vf.PostFunc(tn)
+}
+
+// These constants denote which hook of a node Walk calls next.
+const (
+	walkPre = iota
+	walkIn
+	walkPost
+)
+
+type walkFrame struct {
+	tn   *Tnode
+	next int
+	skip bool
+}
+
+// Walk walks the tree depth first in the calling goroutine and calls the hooks of v on every
+// node, see Visitor for how the values returned by them steer the walk. If a hook modifies the
+// tree structurally, Walk stops and returns ErrConcurrentModification. The tree is only locked
+// while walking, not while the hooks run.
+func (bt *BSTree) Walk(v Visitor) error {
+	bt.rw.RLock()
+	defer bt.rw.RUnlock()
+
+	if bt.root == nil {
+		return nil
+	}
+	call := func(hook func(tn *Tnode) WalkControl, tn *Tnode) WalkControl {
+		bt.rw.RUnlock()
+		defer bt.rw.RLock()
+		return hook(tn)
+	}
+
+	mods := bt.mods
+
+	ls := stack.NewLStack()
+	ls.Push(&walkFrame{tn: bt.root})
+
+walk:
+	for !ls.Empty() {
+		if bt.mods != mods {
+			return container.ErrConcurrentModification
+		}
+		f := ls.Pop().(*walkFrame)
+
+		switch f.next {
+		case walkPre:
+			c := call(v.Pre, f.tn)
+			if c == Stop {
+				break walk
+			}
+			f.skip = c == SkipSubtree
+			f.next = walkIn
+			ls.Push(f)
+			if !f.skip && f.tn.lightChild != nil {
+				ls.Push(&walkFrame{tn: f.tn.lightChild})
+			}
+		case walkIn:
+			c := call(v.In, f.tn)
+			if c == Stop {
+				break walk
+			}
+			f.skip = f.skip || c == SkipSubtree
+			f.next = walkPost
+			ls.Push(f)
+			if !f.skip && f.tn.rightChild != nil {
+				ls.Push(&walkFrame{tn: f.tn.rightChild})
+			}
+		case walkPost:
+			if call(v.Post, f.tn) == Stop {
+				break walk
+			}
+		}
+	}
+
+	if bt.mods != mods {
+		return container.ErrConcurrentModification
+	}
+	return nil
+}
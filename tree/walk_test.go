@@ -0,0 +1,126 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/testdata"
+	"github.com/NzKSO/container/tree"
+)
+
+// recorder records the IDs of the nodes its hooks are called with.
+type recorder struct {
+	pre, in, post []int
+}
+
+func (rec *recorder) Pre(tn *tree.Tnode) tree.WalkControl {
+	rec.pre = append(rec.pre, tn.GetData().(*testdata.Corp).ID)
+	return tree.Continue
+}
+
+func (rec *recorder) In(tn *tree.Tnode) tree.WalkControl {
+	rec.in = append(rec.in, tn.GetData().(*testdata.Corp).ID)
+	return tree.Continue
+}
+
+func (rec *recorder) Post(tn *tree.Tnode) tree.WalkControl {
+	rec.post = append(rec.post, tn.GetData().(*testdata.Corp).ID)
+	return tree.Continue
+}
+
+func TestBSTreeWalk(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	rec := &recorder{}
+	bt.Walk(rec)
+
+	for i, got := range [][]int{rec.in, rec.pre, rec.post} {
+		want := make([]int, len(index[i]))
+		for j, iv := range index[i] {
+			want[j] = testCase[iv].ID
+		}
+		if !compareIntSlice(got, want) {
+			t.Errorf("%v != %v", got, want)
+		}
+	}
+
+	tree.NewBSTree().Walk(rec)
+}
+
+func TestBSTreeWalkControl(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	// Skipping the subtree of 3 leaves out 0, 1, 2 and 4, but 3 itself gets all hooks.
+	var pre, post []int
+	bt.Walk(tree.VisitorFuncs{
+		PreFunc: func(tn *tree.Tnode) tree.WalkControl {
+			pre = append(pre, tn.GetData().(*testdata.Corp).ID)
+			if tn.GetData().(*testdata.Corp).ID == 3 {
+				return tree.SkipSubtree
+			}
+			return tree.Continue
+		},
+		PostFunc: func(tn *tree.Tnode) tree.WalkControl {
+			post = append(post, tn.GetData().(*testdata.Corp).ID)
+			return tree.Continue
+		},
+	})
+	if want := []int{5, 3, 7, 6, 9, 8, 10}; !compareIntSlice(pre, want) {
+		t.Errorf("%v != %v", pre, want)
+	}
+	if want := []int{3, 6, 8, 10, 9, 7, 5}; !compareIntSlice(post, want) {
+		t.Errorf("%v != %v", post, want)
+	}
+
+	// Skipping in In leaves out the right subtree only.
+	var in []int
+	bt.Walk(tree.VisitorFuncs{
+		InFunc: func(tn *tree.Tnode) tree.WalkControl {
+			in = append(in, tn.GetData().(*testdata.Corp).ID)
+			if tn.GetData().(*testdata.Corp).ID == 5 {
+				return tree.SkipSubtree
+			}
+			return tree.Continue
+		},
+	})
+	if want := []int{0, 1, 2, 3, 4, 5}; !compareIntSlice(in, want) {
+		t.Errorf("%v != %v", in, want)
+	}
+
+	// Stop ends the walk at the third node in order.
+	in = in[:0]
+	bt.Walk(tree.VisitorFuncs{
+		InFunc: func(tn *tree.Tnode) tree.WalkControl {
+			in = append(in, tn.GetData().(*testdata.Corp).ID)
+			if len(in) == 3 {
+				return tree.Stop
+			}
+			return tree.Continue
+		},
+	})
+	if want := []int{0, 1, 2}; !compareIntSlice(in, want) {
+		t.Errorf("%v != %v", in, want)
+	}
+}
+
+func TestBSTreeWalkConcurrentModification(t *testing.T) {
+	bt, _ := createTree(defidx)
+
+	var visited int
+	err := bt.Walk(tree.VisitorFuncs{
+		PreFunc: func(tn *tree.Tnode) tree.WalkControl {
+			visited++
+			if visited == 3 {
+				bt.Delete(tn.GetData().(*testdata.Corp).ID)
+			}
+			return tree.Continue
+		},
+	})
+	if err != container.ErrConcurrentModification || visited != 3 {
+		t.Errorf("(%v != %v) or (%v != 3)", err, container.ErrConcurrentModification, visited)
+	}
+
+	if err := bt.Walk(&recorder{}); err != nil {
+		t.Errorf("%v != nil", err)
+	}
+}
@@ -0,0 +1,285 @@
+package trie
+
+import (
+	"sort"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/stack"
+)
+
+type rnode struct {
+	prefix   string
+	children []*rnode // sorted by the first byte of prefix
+	value    interface{}
+	hasValue bool
+}
+
+// child returns the index of the child of n whose prefix starts with b, or where it would be
+// inserted, and whether it exists.
+func (n *rnode) child(b byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].prefix[0] >= b
+	})
+	return i, i < len(n.children) && n.children[i].prefix[0] == b
+}
+
+// addChild inserts c at its place among the children of n.
+func (n *rnode) addChild(c *rnode) {
+	i, _ := n.child(c.prefix[0])
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+}
+
+// commonPrefixLen returns the length of the longest common prefix of s1 and s2.
+func commonPrefixLen(s1, s2 string) int {
+	i := 0
+	for i < len(s1) && i < len(s2) && s1[i] == s2[i] {
+		i++
+	}
+	return i
+}
+
+// Radix represents a radix tree, a prefix tree where every node without a value has at least
+// two children, so that the edges are labeled with strings rather than bytes.
+type Radix struct {
+	root rnode
+	size int
+}
+
+// NewRadix returns an empty radix tree.
+func NewRadix() *Radix {
+	return &Radix{}
+}
+
+// find returns the node holding exactly key, or nil if there is none.
+func (rt *Radix) find(key string) *rnode {
+	n := &rt.root
+	for key != "" {
+		i, ok := n.child(key[0])
+		if !ok {
+			return nil
+		}
+		c := n.children[i]
+		if len(key) < len(c.prefix) || key[:len(c.prefix)] != c.prefix {
+			return nil
+		}
+		n, key = c, key[len(c.prefix):]
+	}
+	return n
+}
+
+// Insert inserts key with value. If key already exists, returns ErrDataExists.
+func (rt *Radix) Insert(key string, value interface{}) error {
+	n := &rt.root
+	for key != "" {
+		i, ok := n.child(key[0])
+		if !ok {
+			n.addChild(&rnode{prefix: key, value: value, hasValue: true})
+			rt.size++
+			return nil
+		}
+
+		c := n.children[i]
+		l := commonPrefixLen(key, c.prefix)
+		if l < len(c.prefix) {
+			// key leaves the edge halfway, which is split there.
+			mid := &rnode{prefix: c.prefix[:l], children: []*rnode{c}}
+			c.prefix = c.prefix[l:]
+			n.children[i] = mid
+		}
+		n, key = n.children[i], key[l:]
+	}
+
+	if n.hasValue {
+		return container.ErrDataExists
+	}
+	n.value, n.hasValue = value, true
+	rt.size++
+	return nil
+}
+
+// Search returns the value of key. If the tree is empty, returns ErrEmptyTree. If not found,
+// returns ErrNotExist.
+func (rt *Radix) Search(key string) (interface{}, error) {
+	if rt.size == 0 {
+		return nil, container.ErrEmptyTree
+	}
+
+	n := rt.find(key)
+	if n == nil || !n.hasValue {
+		return nil, container.ErrNotExist
+	}
+	return n.value, nil
+}
+
+// Update sets the value of key to value. If the tree is empty, returns ErrEmptyTree. If not
+// found, returns ErrNotExist.
+func (rt *Radix) Update(key string, value interface{}) error {
+	if rt.size == 0 {
+		return container.ErrEmptyTree
+	}
+
+	n := rt.find(key)
+	if n == nil || !n.hasValue {
+		return container.ErrNotExist
+	}
+	n.value = value
+	return nil
+}
+
+// merge absorbs the only child of n into n, if n has no value and isn't the root.
+func (rt *Radix) merge(n *rnode) {
+	if n == &rt.root || n.hasValue || len(n.children) != 1 {
+		return
+	}
+
+	c := n.children[0]
+	n.prefix += c.prefix
+	n.children, n.value, n.hasValue = c.children, c.value, c.hasValue
+}
+
+// Delete deletes key and merges the nodes left with a single child. If the tree is empty,
+// returns ErrEmptyTree. If not found, returns ErrNotExist.
+func (rt *Radix) Delete(key string) error {
+	if rt.size == 0 {
+		return container.ErrEmptyTree
+	}
+
+	var parent *rnode
+	n := &rt.root
+	for key != "" {
+		i, ok := n.child(key[0])
+		if !ok {
+			return container.ErrNotExist
+		}
+		c := n.children[i]
+		if len(key) < len(c.prefix) || key[:len(c.prefix)] != c.prefix {
+			return container.ErrNotExist
+		}
+		parent, n, key = n, c, key[len(c.prefix):]
+	}
+	if !n.hasValue {
+		return container.ErrNotExist
+	}
+
+	n.value, n.hasValue = nil, false
+	rt.size--
+
+	if len(n.children) == 0 && parent != nil {
+		i, _ := parent.child(n.prefix[0])
+		parent.children = append(parent.children[:i], parent.children[i+1:]...)
+		rt.merge(parent)
+	} else {
+		rt.merge(n)
+	}
+	return nil
+}
+
+// descend returns the node whose subtree holds exactly the keys starting with prefix, along
+// with the key leading to it, which extends prefix to the end of the edge. It returns nil if no
+// key starts with prefix.
+func (rt *Radix) descend(prefix string) (*rnode, string) {
+	n, key := &rt.root, ""
+	for rest := prefix; rest != ""; {
+		i, ok := n.child(rest[0])
+		if !ok {
+			return nil, ""
+		}
+		c := n.children[i]
+		l := commonPrefixLen(rest, c.prefix)
+		if l < len(rest) && l < len(c.prefix) {
+			return nil, ""
+		}
+		n, key, rest = c, key+c.prefix, rest[l:]
+	}
+	return n, key
+}
+
+// HasPrefix reports whether any key in the tree starts with prefix.
+func (rt *Radix) HasPrefix(prefix string) bool {
+	n, _ := rt.descend(prefix)
+	// Every node left in the tree leads to a key.
+	return rt.size > 0 && n != nil
+}
+
+// keyRnode pairs a node with the key leading to it.
+type keyRnode struct {
+	n   *rnode
+	key string
+}
+
+// KeysWithPrefix returns the keys starting with prefix in order.
+func (rt *Radix) KeysWithPrefix(prefix string) []string {
+	n, key := rt.descend(prefix)
+	if n == nil {
+		return nil
+	}
+
+	var keys []string
+	ls := stack.NewLStack()
+	ls.Push(keyRnode{n, key})
+	for !ls.Empty() {
+		kn := ls.Pop().(keyRnode)
+		if kn.n.hasValue {
+			keys = append(keys, kn.key)
+		}
+		// Children are pushed in reverse, so that the smallest is popped first.
+		for i := len(kn.n.children) - 1; i >= 0; i-- {
+			c := kn.n.children[i]
+			ls.Push(keyRnode{c, kn.key + c.prefix})
+		}
+	}
+	return keys
+}
+
+// LongestPrefixOf returns the longest key in the tree that s starts with, along with its value.
+// If there is none, returns ErrNotExist.
+func (rt *Radix) LongestPrefixOf(s string) (string, interface{}, error) {
+	var (
+		found bool
+		key   string
+		value interface{}
+	)
+
+	n, depth := &rt.root, 0
+	for {
+		if n.hasValue {
+			found, key, value = true, s[:depth], n.value
+		}
+		if depth == len(s) {
+			break
+		}
+
+		i, ok := n.child(s[depth])
+		if !ok {
+			break
+		}
+		c := n.children[i]
+		if len(s)-depth < len(c.prefix) || s[depth:depth+len(c.prefix)] != c.prefix {
+			break
+		}
+		n, depth = c, depth+len(c.prefix)
+	}
+
+	if !found {
+		return "", nil, container.ErrNotExist
+	}
+	return key, value, nil
+}
+
+// Size returns the number of keys in the tree.
+func (rt *Radix) Size() int {
+	return rt.size
+}
+
+// Empty returns true if the tree has no key, otherwise false.
+func (rt *Radix) Empty() bool {
+	return rt.size == 0
+}
+
+// Reset drops all of keys in the tree and back to its initial state.
+func (rt *Radix) Reset() {
+	rt.root = rnode{}
+	rt.size = 0
+}
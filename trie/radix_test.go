@@ -0,0 +1,73 @@
+package trie_test
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NzKSO/container/trie"
+)
+
+func TestRadixAgainstTrie(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	randomKey := func() string {
+		var sb strings.Builder
+		for n := r.Intn(6); n > 0; n-- {
+			sb.WriteByte("abc"[r.Intn(3)])
+		}
+		return sb.String()
+	}
+
+	rt, tr := trie.NewRadix(), trie.NewTrie()
+	model := make(map[string]int)
+	for i := 0; i < 5000; i++ {
+		key := randomKey()
+		if r.Intn(2) == 0 {
+			err1, err2 := rt.Delete(key), tr.Delete(key)
+			if err1 != err2 {
+				t.Fatalf("Delete(%q): %v != %v", key, err1, err2)
+			}
+			delete(model, key)
+		} else {
+			err1, err2 := rt.Insert(key, i), tr.Insert(key, i)
+			if err1 != err2 {
+				t.Fatalf("Insert(%q): %v != %v", key, err1, err2)
+			}
+			if _, ok := model[key]; !ok {
+				model[key] = i
+			}
+		}
+
+		prefix := randomKey()
+		var want []string
+		for key := range model {
+			if strings.HasPrefix(key, prefix) {
+				want = append(want, key)
+			}
+		}
+		sort.Strings(want)
+		if got := rt.KeysWithPrefix(prefix); !compareStrings(got, want) {
+			t.Fatalf("KeysWithPrefix(%q): %v != %v", prefix, got, want)
+		}
+		if got := tr.KeysWithPrefix(prefix); !compareStrings(got, want) {
+			t.Fatalf("KeysWithPrefix(%q): %v != %v", prefix, got, want)
+		}
+
+		k1, v1, err1 := rt.LongestPrefixOf(prefix)
+		k2, v2, err2 := tr.LongestPrefixOf(prefix)
+		if k1 != k2 || v1 != v2 || err1 != err2 {
+			t.Fatalf("LongestPrefixOf(%q): (%q, %v, %v) != (%q, %v, %v)", prefix, k1, v1, err1, k2, v2, err2)
+		}
+	}
+
+	if rt.Size() != len(model) || tr.Size() != len(model) {
+		t.Errorf("%v, %v != %v", rt.Size(), tr.Size(), len(model))
+	}
+	for key, v := range model {
+		if got, err := rt.Search(key); err != nil || got != v {
+			t.Errorf("(%v != nil) or (%v != %v)", err, got, v)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package trie implements prefix trees for string keys, which find keys by prefix in time
+// proportional to the length of the prefix rather than to the number of keys.
+//
+// Trie has a node for every byte of every key, Radix merges chains of nodes with a single
+// child into one, which saves memory when keys share long prefixes or are sparse. Keys are
+// ordered by their bytes, the same way Go compares strings.
+package trie
+
+import (
+	"sort"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/stack"
+)
+
+type node struct {
+	label    byte
+	children []*node // sorted by label
+	value    interface{}
+	hasValue bool
+}
+
+// child returns the index of the child of n labeled b, or where it would be inserted, and
+// whether it exists.
+func (n *node) child(b byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label >= b
+	})
+	return i, i < len(n.children) && n.children[i].label == b
+}
+
+// Trie represents a prefix tree with a node for every byte of its keys.
+type Trie struct {
+	root node
+	size int
+}
+
+// NewTrie returns an empty trie.
+func NewTrie() *Trie {
+	return &Trie{}
+}
+
+// find returns the node reached by following key from the root, or nil if there is none.
+func (t *Trie) find(key string) *node {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			return nil
+		}
+		n = n.children[j]
+	}
+	return n
+}
+
+// Insert inserts key with value. If key already exists, returns ErrDataExists.
+func (t *Trie) Insert(key string, value interface{}) error {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			n.children = append(n.children, nil)
+			copy(n.children[j+1:], n.children[j:])
+			n.children[j] = &node{label: key[i]}
+		}
+		n = n.children[j]
+	}
+
+	if n.hasValue {
+		return container.ErrDataExists
+	}
+	n.value, n.hasValue = value, true
+	t.size++
+	return nil
+}
+
+// Search returns the value of key. If the trie is empty, returns ErrEmptyTree. If not found,
+// returns ErrNotExist.
+func (t *Trie) Search(key string) (interface{}, error) {
+	if t.size == 0 {
+		return nil, container.ErrEmptyTree
+	}
+
+	n := t.find(key)
+	if n == nil || !n.hasValue {
+		return nil, container.ErrNotExist
+	}
+	return n.value, nil
+}
+
+// Update sets the value of key to value. If the trie is empty, returns ErrEmptyTree. If not
+// found, returns ErrNotExist.
+func (t *Trie) Update(key string, value interface{}) error {
+	if t.size == 0 {
+		return container.ErrEmptyTree
+	}
+
+	n := t.find(key)
+	if n == nil || !n.hasValue {
+		return container.ErrNotExist
+	}
+	n.value = value
+	return nil
+}
+
+// Delete deletes key and removes the nodes no other key passes through. If the trie is empty,
+// returns ErrEmptyTree. If not found, returns ErrNotExist.
+func (t *Trie) Delete(key string) error {
+	if t.size == 0 {
+		return container.ErrEmptyTree
+	}
+
+	path := make([]*node, 0, len(key)+1)
+	n := &t.root
+	path = append(path, n)
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			return container.ErrNotExist
+		}
+		n = n.children[j]
+		path = append(path, n)
+	}
+	if !n.hasValue {
+		return container.ErrNotExist
+	}
+
+	n.value, n.hasValue = nil, false
+	t.size--
+
+	// Remove the nodes left without a value or children, bottom up.
+	for i := len(path) - 1; i > 0 && !path[i].hasValue && len(path[i].children) == 0; i-- {
+		parent := path[i-1]
+		j, _ := parent.child(path[i].label)
+		parent.children = append(parent.children[:j], parent.children[j+1:]...)
+	}
+	return nil
+}
+
+// HasPrefix reports whether any key in the trie starts with prefix.
+func (t *Trie) HasPrefix(prefix string) bool {
+	// Every node left in the trie leads to a key.
+	return t.size > 0 && t.find(prefix) != nil
+}
+
+// keyNode pairs a node with the key leading to it.
+type keyNode struct {
+	n   *node
+	key string
+}
+
+// KeysWithPrefix returns the keys starting with prefix in order.
+func (t *Trie) KeysWithPrefix(prefix string) []string {
+	n := t.find(prefix)
+	if n == nil {
+		return nil
+	}
+
+	var keys []string
+	ls := stack.NewLStack()
+	ls.Push(keyNode{n, prefix})
+	for !ls.Empty() {
+		kn := ls.Pop().(keyNode)
+		if kn.n.hasValue {
+			keys = append(keys, kn.key)
+		}
+		// Children are pushed in reverse, so that the smallest is popped first.
+		for i := len(kn.n.children) - 1; i >= 0; i-- {
+			c := kn.n.children[i]
+			ls.Push(keyNode{c, kn.key + string(c.label)})
+		}
+	}
+	return keys
+}
+
+// LongestPrefixOf returns the longest key in the trie that s starts with, along with its value.
+// If there is none, returns ErrNotExist.
+func (t *Trie) LongestPrefixOf(s string) (string, interface{}, error) {
+	var (
+		found bool
+		key   string
+		value interface{}
+	)
+
+	n := &t.root
+	for i := 0; ; i++ {
+		if n.hasValue {
+			found, key, value = true, s[:i], n.value
+		}
+		if i == len(s) {
+			break
+		}
+
+		j, ok := n.child(s[i])
+		if !ok {
+			break
+		}
+		n = n.children[j]
+	}
+
+	if !found {
+		return "", nil, container.ErrNotExist
+	}
+	return key, value, nil
+}
+
+// Size returns the number of keys in the trie.
+func (t *Trie) Size() int {
+	return t.size
+}
+
+// Empty returns true if the trie has no key, otherwise false.
+func (t *Trie) Empty() bool {
+	return t.size == 0
+}
+
+// Reset drops all of keys in the trie and back to its initial state.
+func (t *Trie) Reset() {
+	t.root = node{}
+	t.size = 0
+}
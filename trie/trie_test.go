@@ -0,0 +1,190 @@
+package trie_test
+
+import (
+	"testing"
+
+	"github.com/NzKSO/container"
+	"github.com/NzKSO/container/trie"
+)
+
+// prefixTree is implemented by both Trie and Radix.
+type prefixTree interface {
+	Insert(key string, value interface{}) error
+	Search(key string) (interface{}, error)
+	Update(key string, value interface{}) error
+	Delete(key string) error
+	HasPrefix(prefix string) bool
+	KeysWithPrefix(prefix string) []string
+	LongestPrefixOf(s string) (string, interface{}, error)
+	Size() int
+	Empty() bool
+	Reset()
+}
+
+var constructors = map[string]func() prefixTree{
+	"Trie":  func() prefixTree { return trie.NewTrie() },
+	"Radix": func() prefixTree { return trie.NewRadix() },
+}
+
+var words = []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom", "r", ""}
+
+func compareStrings(s1, s2 []string) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if s1[i] != s2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func createTree(t *testing.T, name string) prefixTree {
+	pt := constructors[name]()
+	for i, w := range words {
+		if err := pt.Insert(w, i); err != nil {
+			t.Fatalf("%v: %v != nil", name, err)
+		}
+	}
+	return pt
+}
+
+func TestInsertSearch(t *testing.T) {
+	for name, create := range constructors {
+		pt := create()
+		if _, err := pt.Search("rom"); err != container.ErrEmptyTree {
+			t.Errorf("%v: %v != %v", name, err, container.ErrEmptyTree)
+		}
+
+		pt = createTree(t, name)
+		if pt.Size() != len(words) || pt.Empty() {
+			t.Errorf("%v: %v != %v", name, pt.Size(), len(words))
+		}
+		for i, w := range words {
+			if v, err := pt.Search(w); err != nil || v != i {
+				t.Errorf("%v: (%v != nil) or (%v != %v)", name, err, v, i)
+			}
+			if err := pt.Insert(w, -1); err != container.ErrDataExists {
+				t.Errorf("%v: %v != %v", name, err, container.ErrDataExists)
+			}
+		}
+		for _, w := range []string{"ro", "roman", "romanes", "rubi", "x"} {
+			if _, err := pt.Search(w); err != container.ErrNotExist {
+				t.Errorf("%v: Search(%q): %v != %v", name, w, err, container.ErrNotExist)
+			}
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	for name := range constructors {
+		pt := createTree(t, name)
+		if err := pt.Update("rubens", "painter"); err != nil {
+			t.Errorf("%v: %v != nil", name, err)
+		}
+		if v, _ := pt.Search("rubens"); v != "painter" {
+			t.Errorf("%v: %v != painter", name, v)
+		}
+		if err := pt.Update("rube", 0); err != container.ErrNotExist {
+			t.Errorf("%v: %v != %v", name, err, container.ErrNotExist)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	for name := range constructors {
+		pt := createTree(t, name)
+		for _, w := range []string{"rom", "ruber", "", "romulus"} {
+			if err := pt.Delete(w); err != nil {
+				t.Errorf("%v: Delete(%q): %v != nil", name, w, err)
+			}
+			if err := pt.Delete(w); err != container.ErrNotExist {
+				t.Errorf("%v: Delete(%q): %v != %v", name, w, err, container.ErrNotExist)
+			}
+		}
+		if err := pt.Delete("roma"); err != container.ErrNotExist {
+			t.Errorf("%v: %v != %v", name, err, container.ErrNotExist)
+		}
+
+		want := []string{"r", "romane", "romanus", "rubens", "rubicon", "rubicundus"}
+		if got := pt.KeysWithPrefix(""); !compareStrings(got, want) {
+			t.Errorf("%v: %v != %v", name, got, want)
+		}
+		for i, w := range words {
+			if v, err := pt.Search(w); err == nil && v != i {
+				t.Errorf("%v: %v != %v", name, v, i)
+			}
+		}
+
+		for _, w := range want {
+			pt.Delete(w)
+		}
+		if !pt.Empty() || pt.HasPrefix("") {
+			t.Errorf("%v: tree is not empty", name)
+		}
+		if err := pt.Delete("r"); err != container.ErrEmptyTree {
+			t.Errorf("%v: %v != %v", name, err, container.ErrEmptyTree)
+		}
+	}
+}
+
+func TestPrefixes(t *testing.T) {
+	for name := range constructors {
+		pt := createTree(t, name)
+
+		tests := []struct {
+			prefix string
+			want   []string
+		}{
+			{"rom", []string{"rom", "romane", "romanus", "romulus"}},
+			{"roma", []string{"romane", "romanus"}},
+			{"rubic", []string{"rubicon", "rubicundus"}},
+			{"rubicon", []string{"rubicon"}},
+			{"rubicons", nil},
+			{"ra", nil},
+			{"", []string{"", "r", "rom", "romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"}},
+		}
+		for _, test := range tests {
+			if got := pt.KeysWithPrefix(test.prefix); !compareStrings(got, test.want) {
+				t.Errorf("%v: KeysWithPrefix(%q): %v != %v", name, test.prefix, got, test.want)
+			}
+			if got := pt.HasPrefix(test.prefix); got != (test.want != nil) {
+				t.Errorf("%v: HasPrefix(%q): %v != %v", name, test.prefix, got, test.want != nil)
+			}
+		}
+
+		longest := map[string]string{
+			"romanesque": "romane",
+			"romans":     "rom",
+			"rubicund":   "r",
+			"x":          "",
+			"rom":        "rom",
+		}
+		for s, want := range longest {
+			if key, v, err := pt.LongestPrefixOf(s); err != nil || key != want {
+				t.Errorf("%v: LongestPrefixOf(%q): (%v != nil) or (%q != %q)", name, s, err, key, want)
+			} else if sv, _ := pt.Search(key); sv != v {
+				t.Errorf("%v: %v != %v", name, v, sv)
+			}
+		}
+
+		pt.Delete("")
+		if _, _, err := pt.LongestPrefixOf("x"); err != container.ErrNotExist {
+			t.Errorf("%v: %v != %v", name, err, container.ErrNotExist)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	for name := range constructors {
+		pt := createTree(t, name)
+		pt.Reset()
+		if !pt.Empty() || pt.Size() != 0 || pt.KeysWithPrefix("") != nil {
+			t.Errorf("%v: %v != 0", name, pt.Size())
+		}
+		if err := pt.Insert("romulus", 0); err != nil {
+			t.Errorf("%v: %v != nil", name, err)
+		}
+	}
+}